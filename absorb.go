@@ -0,0 +1,62 @@
+package isaac
+
+import "io"
+
+var (
+	_ io.Writer = (*Isaac)(nil)
+	_ io.Writer = (*Isaac64)(nil)
+)
+
+// Write absorbs p into the state of ISAAC instance. The bytes are XORed into
+// the internal memory, little-endian, so they take effect from the next
+// block. Each time as many bytes as a full seed have been absorbed, the
+// memory is folded into the results and the state is mixed as by Seed, so
+// that the output depends on everything written. Splitting the same bytes
+// over several calls gives the same state. Write never fails on a usable
+// instance.
+func (ctx *Isaac) Write(p []byte) (int, error) {
+	if ctx.wiped {
+		return 0, errWiped
+	}
+	r, m := ctx.rsl(), ctx.mem()
+	size := 4 * len(m)
+	for _, c := range p {
+		i := ctx.absorbed
+		m[i/4] ^= uint32(c) << (8 * (i % 4))
+		if ctx.absorbed++; int(ctx.absorbed) == size {
+			for j := range r {
+				r[j] ^= m[j]
+			}
+			ctx.randInit(true)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Write absorbs p into the state of ISAAC64 instance. The bytes are XORed
+// into the internal memory, little-endian, so they take effect from the next
+// block. Each time as many bytes as a full seed have been absorbed, the
+// memory is folded into the results and the state is mixed as by Seed, so
+// that the output depends on everything written. Splitting the same bytes
+// over several calls gives the same state. Write never fails on a usable
+// instance.
+func (ctx *Isaac64) Write(p []byte) (int, error) {
+	if ctx.wiped {
+		return 0, errWiped
+	}
+	r, m := ctx.rsl(), ctx.mem()
+	size := 8 * len(m)
+	for _, c := range p {
+		i := ctx.absorbed
+		m[i/8] ^= uint64(c) << (8 * (i % 8))
+		if ctx.absorbed++; int(ctx.absorbed) == size {
+			for j := range r {
+				r[j] ^= m[j]
+			}
+			ctx.randInit(true)
+		}
+	}
+
+	return len(p), nil
+}
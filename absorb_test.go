@@ -0,0 +1,101 @@
+package isaac
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	input := make([]byte, 3000)
+	for i := range input {
+		input[i] = byte(i*31 + 7)
+	}
+
+	whole := NewIsaac()
+	whole.Seed(1)
+	if n, err := whole.Write(input); n != len(input) || err != nil {
+		t.Fatalf("Write returned %v, %v", n, err)
+	}
+
+	// Splitting the input does not change the state.
+	split := NewIsaac()
+	split.Seed(1)
+	for p := input; len(p) > 0; {
+		n := 1 + len(p)%97
+		if n > len(p) {
+			n = len(p)
+		}
+		split.Write(p[:n])
+		p = p[n:]
+	}
+	if whole.randrsl != split.randrsl || whole.randmem != split.randmem || whole.absorbed != split.absorbed {
+		t.Fatal("split writes differ")
+	}
+
+	// 3000 bytes mix the state twice and leave 952 bytes pending.
+	if whole.absorbed != 3000-2*1024 || whole.randcnt != 256 {
+		t.Fatalf("unexpected state after write: absorbed %v, randcnt %v", whole.absorbed, whole.randcnt)
+	}
+
+	// A single changed byte changes the output, even before mixing.
+	other := NewIsaac()
+	other.Seed(1)
+	input[2999] ^= 1
+	other.Write(input)
+	for i := 0; i < 256; i++ {
+		whole.Uint32()
+		other.Uint32()
+	}
+	if whole.Uint32() == other.Uint32() {
+		t.Fatal("output does not depend on the last byte")
+	}
+
+	var wiped Isaac
+	wiped.Wipe()
+	if _, err := wiped.Write(input); err != errWiped {
+		t.Fatalf("expected %v, got %v", errWiped, err)
+	}
+}
+
+func TestIsaac64Write(t *testing.T) {
+	input := []byte("player moved north; player opened chest; player moved east")
+
+	a, b := NewIsaac64(), NewIsaac64()
+	a.Seed(1)
+	b.Seed(1)
+	for i := 0; i < 100; i++ {
+		a.Write(input)
+	}
+	b.Write(bytes.Repeat(input, 100))
+	if a.absorbed != uint64(100*len(input)%2048) || a.randmem != b.randmem {
+		t.Fatal("split writes differ")
+	}
+	for i := 0; i < 600; i++ {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("[%v] %x != %x", i, x, y)
+		}
+	}
+
+	c := NewIsaac64()
+	c.Seed(1)
+	c.Write(bytes.Repeat(input, 99))
+	if a.Uint64() == c.Uint64() {
+		t.Fatal("output does not depend on the input")
+	}
+
+	// The pending count is part of the encoded state.
+	b.Write(input[:5])
+	data, _ := b.MarshalBinary()
+	d := NewIsaac64()
+	if err := d.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if d.absorbed != b.absorbed {
+		t.Fatalf("absorbed %v expected but found %v", b.absorbed, d.absorbed)
+	}
+
+	b.Seed(1)
+	if b.absorbed != 0 {
+		t.Fatal("seeding did not reset the pending count")
+	}
+}
@@ -0,0 +1,22 @@
+#!/bin/sh
+#
+# bench.sh runs the benchmarks of the module and stores the results in
+# bench/<name>.txt, which is in the format read by benchstat:
+#
+#	bench/bench.sh new
+#	benchstat bench/baseline.txt bench/new.txt
+#
+# name defaults to the current commit. BENCH, BENCH_COUNT and BENCH_TIME
+# select the benchmarks, the number of runs and the time of each run.
+
+set -e
+
+cd "$(dirname "$0")/.."
+
+name=${1:-$(git rev-parse --short HEAD)}
+
+go test -run '^$' \
+	-bench "${BENCH:-.}" \
+	-count "${BENCH_COUNT:-10}" \
+	-benchtime "${BENCH_TIME:-1s}" \
+	./... | tee "bench/$name.txt"
@@ -0,0 +1,182 @@
+package isaac
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+const benchBufSize = 4096
+
+var (
+	sink32 uint32
+	sink64 uint64
+)
+
+func BenchmarkIsaacUint32(b *testing.B) {
+	isa := NewIsaac()
+	isa.Seed(1)
+	b.SetBytes(4)
+
+	for i := 0; i < b.N; i++ {
+		sink32 = isa.Uint32()
+	}
+}
+
+func BenchmarkIsaacUint64(b *testing.B) {
+	isa := NewIsaac()
+	isa.Seed(1)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		sink64 = isa.Uint64()
+	}
+}
+
+func BenchmarkIsaac64Uint32(b *testing.B) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+	b.SetBytes(4)
+
+	for i := 0; i < b.N; i++ {
+		sink32 = isa.Uint32()
+	}
+}
+
+func BenchmarkIsaac64Uint64(b *testing.B) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		sink64 = isa.Uint64()
+	}
+}
+
+func BenchmarkIsaacFill(b *testing.B) {
+	isa := NewIsaac()
+	isa.Seed(1)
+	buf := make([]byte, benchBufSize)
+	b.SetBytes(benchBufSize)
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(buf); j += 4 {
+			binary.LittleEndian.PutUint32(buf[j:], isa.Uint32())
+		}
+	}
+}
+
+func BenchmarkIsaac64Fill(b *testing.B) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+	buf := make([]byte, benchBufSize)
+	b.SetBytes(benchBufSize)
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(buf); j += 8 {
+			binary.LittleEndian.PutUint64(buf[j:], isa.Uint64())
+		}
+	}
+}
+
+func BenchmarkIsaacSeed(b *testing.B) {
+	isa := NewIsaac()
+
+	for i := 0; i < b.N; i++ {
+		isa.Seed(int64(i))
+	}
+}
+
+func BenchmarkIsaacSeedBytes(b *testing.B) {
+	isa := NewIsaac()
+	seed := make([]byte, 1024)
+	b.SetBytes(int64(len(seed)))
+
+	for i := 0; i < b.N; i++ {
+		isa.SeedBytes(seed)
+	}
+}
+
+func BenchmarkIsaac64Seed(b *testing.B) {
+	isa := NewIsaac64()
+
+	for i := 0; i < b.N; i++ {
+		isa.Seed(int64(i))
+	}
+}
+
+func BenchmarkIsaac64SeedBytes(b *testing.B) {
+	isa := NewIsaac64()
+	seed := make([]byte, 2048)
+	b.SetBytes(int64(len(seed)))
+
+	for i := 0; i < b.N; i++ {
+		isa.SeedBytes(seed)
+	}
+}
+
+func BenchmarkIsaacRandInit(b *testing.B) {
+	isa := NewIsaac()
+
+	for i := 0; i < b.N; i++ {
+		isa.randInit(true)
+	}
+}
+
+func BenchmarkIsaac64RandInit(b *testing.B) {
+	isa := NewIsaac64()
+
+	for i := 0; i < b.N; i++ {
+		isa.randInit(true)
+	}
+}
+
+func BenchmarkIsaacBlock(b *testing.B) {
+	isa := NewIsaac()
+	isa.Seed(1)
+	b.SetBytes(int64(len(isa.randrsl) * 4))
+
+	for i := 0; i < b.N; i++ {
+		isa.isaac()
+	}
+}
+
+func BenchmarkIsaac64Block(b *testing.B) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+	b.SetBytes(int64(len(isa.randrsl) * 8))
+
+	for i := 0; i < b.N; i++ {
+		isa.isaac64()
+	}
+}
+
+func BenchmarkMathRandUint32(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	b.SetBytes(4)
+
+	for i := 0; i < b.N; i++ {
+		sink32 = r.Uint32()
+	}
+}
+
+func BenchmarkMathRandUint64(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		sink64 = r.Uint64()
+	}
+}
+
+func BenchmarkCryptoRandFill(b *testing.B) {
+	buf := make([]byte, benchBufSize)
+	b.SetBytes(benchBufSize)
+
+	for i := 0; i < b.N; i++ {
+		if _, err := crand.Read(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
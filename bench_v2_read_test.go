@@ -0,0 +1,21 @@
+//go:build go1.23
+// +build go1.23
+
+package isaac
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// ChaCha8.Read is new in Go 1.23; the other math/rand/v2 benchmarks only
+// need Go 1.22.
+func BenchmarkChaCha8Fill(b *testing.B) {
+	r := rand.NewChaCha8([32]byte{})
+	buf := make([]byte, benchBufSize)
+	b.SetBytes(benchBufSize)
+
+	for i := 0; i < b.N; i++ {
+		r.Read(buf)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build go1.22
+// +build go1.22
+
+package isaac
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func BenchmarkPCGUint64(b *testing.B) {
+	r := rand.NewPCG(1, 2)
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		sink64 = r.Uint64()
+	}
+}
+
+func BenchmarkChaCha8Uint64(b *testing.B) {
+	r := rand.NewChaCha8([32]byte{})
+	b.SetBytes(8)
+
+	for i := 0; i < b.N; i++ {
+		sink64 = r.Uint64()
+	}
+}
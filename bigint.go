@@ -0,0 +1,86 @@
+package isaac
+
+import "math/big"
+
+var bigOne = big.NewInt(1)
+
+// BigBits returns a uniform integer in [0, 2^n). It takes ceil(n/64) values
+// of Uint64, least significant first, and drops the excess high bits of the
+// last one, so the result does not depend on the platform. It panics if n is
+// negative.
+func (ctx *Isaac64) BigBits(n int) *big.Int {
+	if n < 0 {
+		panic("isaac: invalid argument to BigBits")
+	}
+
+	words := (n + 63) / 64
+	b := make([]byte, 8*words)
+	for i := 0; i < words; i++ {
+		w := ctx.Uint64()
+		if i == words-1 && n%64 != 0 {
+			w &= 1<<uint(n%64) - 1
+		}
+
+		// big.Int.SetBytes takes big-endian bytes.
+		off := len(b) - 8*(i+1)
+		for j := 0; j < 8; j++ {
+			b[off+7-j] = byte(w >> (8 * uint(j)))
+		}
+	}
+
+	return new(big.Int).SetBytes(b)
+}
+
+// BigInt returns a uniform integer in [0, max) by rejection, drawing
+// BigBits of the bit length of max-1 until the value is below max. It panics
+// if max <= 0.
+func (ctx *Isaac64) BigInt(max *big.Int) *big.Int {
+	if max.Sign() <= 0 {
+		panic("isaac: argument to BigInt is <= 0")
+	}
+
+	n := new(big.Int).Sub(max, bigOne).BitLen()
+	for {
+		if v := ctx.BigBits(n); v.Cmp(max) < 0 {
+			return v
+		}
+	}
+}
+
+// ProbablePrime returns a number of exactly the given bit length that passes
+// the primality test of big.Int.ProbablyPrime with 20 rounds, which is itself
+// deterministic. Candidates are drawn by BigBits with the top and, for more
+// than two bits, the bottom bit set. It panics if bits < 2.
+func (ctx *Isaac64) ProbablePrime(bits int) *big.Int {
+	if bits < 2 {
+		panic("isaac: prime size must be at least 2 bits")
+	}
+
+	for {
+		p := ctx.BigBits(bits)
+		p.SetBit(p, bits-1, 1)
+		if bits > 2 {
+			p.SetBit(p, 0, 1)
+		}
+		if p.ProbablyPrime(20) {
+			return p
+		}
+	}
+}
+
+// BigUnit returns a uniform element of the multiplicative group of integers
+// modulo n, that is a number in [1, n) coprime to n, by drawing BigInt(n)
+// until one is found. It panics if n < 2.
+func (ctx *Isaac64) BigUnit(n *big.Int) *big.Int {
+	if n.Cmp(bigOne) <= 0 {
+		panic("isaac: argument to BigUnit is < 2")
+	}
+
+	var g big.Int
+	for {
+		v := ctx.BigInt(n)
+		if v.Sign() != 0 && g.GCD(nil, nil, v, n).Cmp(bigOne) == 0 {
+			return v
+		}
+	}
+}
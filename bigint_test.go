@@ -0,0 +1,103 @@
+package isaac
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBigBits(t *testing.T) {
+	isa, ref := NewIsaac64(), NewIsaac64()
+	isa.Seed(1)
+	ref.Seed(1)
+
+	if v, n := isa.BigBits(64), ref.Uint64(); !v.IsUint64() || v.Uint64() != n {
+		t.Fatalf("%x expected but found %x", n, v)
+	}
+
+	// 100 bits take two words, least significant first.
+	lo, hi := ref.Uint64(), ref.Uint64()&(1<<36-1)
+	want := new(big.Int).Lsh(new(big.Int).SetUint64(hi), 64)
+	want.Or(want, new(big.Int).SetUint64(lo))
+	if v := isa.BigBits(100); v.Cmp(want) != 0 {
+		t.Fatalf("%x expected but found %x", want, v)
+	}
+
+	if v := isa.BigBits(0); v.Sign() != 0 {
+		t.Fatalf("0 expected but found %v", v)
+	}
+	for i := 0; i < 1000; i++ {
+		if v := isa.BigBits(70); v.BitLen() > 70 {
+			t.Fatalf("%x has more than 70 bits", v)
+		}
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+
+	// A bound just above a power of two rejects about half of the draws.
+	max := new(big.Int).Lsh(bigOne, 130)
+	max.Add(max, bigOne)
+	for i := 0; i < 1000; i++ {
+		if v := isa.BigInt(max); v.Sign() < 0 || v.Cmp(max) >= 0 {
+			t.Fatalf("%v out of range", v)
+		}
+	}
+
+	count := make([]int, 6)
+	for i := 0; i < 6000; i++ {
+		count[isa.BigInt(big.NewInt(6)).Int64()]++
+	}
+	for v, c := range count {
+		if c < 850 || c > 1150 {
+			t.Fatalf("%v drawn %v times out of 6000", v, c)
+		}
+	}
+
+	if v := isa.BigInt(bigOne); v.Sign() != 0 {
+		t.Fatalf("0 expected but found %v", v)
+	}
+}
+
+func TestProbablePrime(t *testing.T) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+	for _, bits := range []int{2, 3, 16, 64, 127, 256, 512} {
+		p := isa.ProbablePrime(bits)
+		if p.BitLen() != bits || !p.ProbablyPrime(20) {
+			t.Fatalf("%v bits: %v", bits, p)
+		}
+	}
+
+	// The same seed gives the same prime on every platform.
+	isa.Seed(1)
+	if p := isa.ProbablePrime(128); p.Text(16) != "dc3dc1d6e1e264ebdead719b9d683d21" {
+		t.Fatalf("unexpected prime %x", p)
+	}
+}
+
+func TestBigUnit(t *testing.T) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+
+	n := big.NewInt(2 * 3 * 5 * 7)
+	var g big.Int
+	seen := make(map[int64]bool)
+	for i := 0; i < 10000; i++ {
+		v := isa.BigUnit(n)
+		if v.Sign() <= 0 || v.Cmp(n) >= 0 || g.GCD(nil, nil, v, n).Cmp(bigOne) != 0 {
+			t.Fatalf("%v is not a unit modulo %v", v, n)
+		}
+		seen[v.Int64()] = true
+	}
+
+	// phi(210) = 48
+	if len(seen) != 48 {
+		t.Fatalf("%v units drawn, 48 expected", len(seen))
+	}
+
+	if v := isa.BigUnit(big.NewInt(2)); v.Cmp(bigOne) != 0 {
+		t.Fatalf("1 expected but found %v", v)
+	}
+}
@@ -0,0 +1,158 @@
+package isaac
+
+import "math"
+
+// bitReservoir buffers the unused bits of a word for Bool, Bits and
+// Bernoulli. Bits are handed out from the least significant end.
+type bitReservoir struct {
+	buf uint64
+	n   uint
+}
+
+// take returns the next k bits, k <= 64, calling word for a new word of the
+// given width whenever the reservoir runs empty. Bits of an older word end up
+// in the lower part of the result.
+func (r *bitReservoir) take(k uint, word func() uint64, width uint) uint64 {
+	var v uint64
+	for got := uint(0); got < k; {
+		if r.n == 0 {
+			r.buf, r.n = word(), width
+		}
+
+		m := k - got
+		if m > r.n {
+			m = r.n
+		}
+		v |= (r.buf & (1<<m - 1)) << got
+		r.buf >>= m
+		r.n -= m
+		got += m
+	}
+
+	return v
+}
+
+// words returns the number of words of the given width that take needs to
+// draw for k bits.
+func (r *bitReservoir) words(k, width uint) uint64 {
+	if k <= r.n {
+		return 0
+	}
+
+	return uint64((k - r.n + width - 1) / width)
+}
+
+// bernoulli draws the bits of a uniform number u in [0, 1) one by one and
+// compares them with the binary expansion of p, returning u < p. It uses two
+// bits on average.
+func (r *bitReservoir) bernoulli(p float64, word func() uint64, width uint) bool {
+	if math.IsNaN(p) {
+		panic("isaac: invalid argument to Bernoulli")
+	}
+	if p <= 0 {
+		return false
+	}
+	if p >= 1 {
+		return true
+	}
+
+	// Doubling p and dropping its integer part is exact in floating point
+	// and yields the next bit of its expansion.
+	for p != 0 {
+		p *= 2
+		pb := p >= 1
+		if pb {
+			p--
+		}
+
+		if ub := r.take(1, word, width) == 1; ub != pb {
+			return pb
+		}
+	}
+
+	return false
+}
+
+// Bool returns a random bit as a bool. It takes one bit of a word and keeps
+// the other 31 for later calls of Bool, Bits and Bernoulli, which are served
+// from the same buffer of bits, lowest bit first. Other methods do not use
+// or consume buffered bits; they continue with the next word of the stream.
+// The buffer is dropped when the instance is seeded again, and is not part of
+// the state encoded by MarshalBinary.
+func (ctx *Isaac) Bool() bool {
+	if ctx.resv.n == 0 {
+		ctx.fillBits()
+	}
+
+	b := ctx.resv.buf & 1
+	ctx.resv.buf >>= 1
+	ctx.resv.n--
+	return b != 0
+}
+
+// Bits returns n random bits, 0 <= n <= 64, taken from the buffer of Bool
+// first and then from as many words as needed. Bits taken earlier are in the
+// lower part of the result. It panics if n is out of range.
+func (ctx *Isaac) Bits(n int) uint64 {
+	if n < 0 || n > 64 {
+		panic("isaac: invalid argument to Bits")
+	}
+
+	return ctx.resv.take(uint(n), ctx.word, 32)
+}
+
+// Bernoulli returns true with probability p, exactly for every float64 p, by
+// comparing p bit by bit with a uniform number drawn from the buffer of Bool.
+// It uses two bits on average and none if p <= 0 or p >= 1. It panics if p
+// is NaN.
+func (ctx *Isaac) Bernoulli(p float64) bool {
+	return ctx.resv.bernoulli(p, ctx.word, 32)
+}
+
+func (ctx *Isaac) word() uint64 {
+	return uint64(ctx.next())
+}
+
+func (ctx *Isaac) fillBits() {
+	ctx.resv.buf, ctx.resv.n = uint64(ctx.next()), 32
+}
+
+// Bool returns a random bit as a bool. It takes one bit of a word and keeps
+// the other 63 for later calls of Bool, Bits and Bernoulli, which are served
+// from the same buffer of bits, lowest bit first. Other methods do not use
+// or consume buffered bits; they continue with the next word of the stream.
+// The buffer is dropped when the instance is seeded again, and is not part of
+// the state encoded by MarshalBinary.
+func (ctx *Isaac64) Bool() bool {
+	if ctx.resv.n == 0 {
+		ctx.fillBits()
+	}
+
+	b := ctx.resv.buf & 1
+	ctx.resv.buf >>= 1
+	ctx.resv.n--
+	return b != 0
+}
+
+// Bits returns n random bits, 0 <= n <= 64, taken from the buffer of Bool
+// first and then from as many words as needed. Bits taken earlier are in the
+// lower part of the result. It panics if n is out of range.
+func (ctx *Isaac64) Bits(n int) uint64 {
+	if n < 0 || n > 64 {
+		panic("isaac: invalid argument to Bits")
+	}
+
+	return ctx.resv.take(uint(n), ctx.next, 64)
+}
+
+// Bernoulli returns true with probability p, exactly for every float64 p, by
+// comparing p bit by bit with a uniform number drawn from the buffer of Bool.
+// It uses two bits on average and none if p <= 0 or p >= 1. It panics if p
+// is NaN.
+func (ctx *Isaac64) Bernoulli(p float64) bool {
+	return ctx.resv.bernoulli(p, ctx.next, 64)
+}
+
+func (ctx *Isaac64) fillBits() {
+	ctx.resv.buf, ctx.resv.n = ctx.next(), 64
+}
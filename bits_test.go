@@ -0,0 +1,182 @@
+package isaac
+
+import "testing"
+
+// bitStream reads the words of a generator as a stream of bits, lowest bit
+// of each word first.
+type bitStream struct {
+	word  func() uint64
+	width uint
+	buf   uint64
+	n     uint
+}
+
+func (s *bitStream) bit() uint64 {
+	if s.n == 0 {
+		s.buf, s.n = s.word(), s.width
+	}
+	b := s.buf & 1
+	s.buf >>= 1
+	s.n--
+	return b
+}
+
+func (s *bitStream) bits(k int) uint64 {
+	var v uint64
+	for i := 0; i < k; i++ {
+		v |= s.bit() << uint(i)
+	}
+	return v
+}
+
+func TestBits(t *testing.T) {
+	isa, ref := NewIsaac(), NewIsaac()
+	isa.Seed(1)
+	ref.Seed(1)
+	s := bitStream{word: func() uint64 { return uint64(ref.Uint32()) }, width: 32}
+
+	for i, k := range []int{1, 20, 20, 64, 0, 31, 33, 64, 5, 1, 1} {
+		if n, v := isa.Bits(k), s.bits(k); n != v {
+			t.Fatalf("[%v] Bits(%v): %x expected but found %x", i, k, v, n)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if n, v := isa.Bool(), s.bit() == 1; n != v {
+			t.Fatalf("[%v] Bool: %v expected but found %v", i, v, n)
+		}
+	}
+
+	isa64, ref64 := NewIsaac64(), NewIsaac64()
+	isa64.Seed(1)
+	ref64.Seed(1)
+	s64 := bitStream{word: ref64.Uint64, width: 64}
+
+	for i, k := range []int{1, 63, 64, 7, 64, 0, 64} {
+		if n, v := isa64.Bits(k), s64.bits(k); n != v {
+			t.Fatalf("[%v] Bits(%v): %x expected but found %x", i, k, v, n)
+		}
+	}
+
+	for _, k := range []int{-1, 65} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Bits(%v): expected panic", k)
+				}
+			}()
+			isa.Bits(k)
+		}()
+	}
+}
+
+func TestBitsConsumption(t *testing.T) {
+	isa, ref := NewIsaac(), NewIsaac()
+	isa.Seed(1)
+	ref.Seed(1)
+
+	// 32 bits take a single word, the 33rd a second one, and other methods
+	// skip the buffered bits.
+	for i := 0; i < 32; i++ {
+		isa.Bool()
+	}
+	ref.Uint32()
+	if n, v := isa.Uint32(), ref.Uint32(); n != v {
+		t.Fatalf("%x expected but found %x", v, n)
+	}
+	isa.Bool()
+	ref.Uint32()
+	if n, v := isa.Uint32(), ref.Uint32(); n != v {
+		t.Fatalf("%x expected but found %x", v, n)
+	}
+
+	isa64, ref64 := NewIsaac64(), NewIsaac64()
+	isa64.Seed(1)
+	ref64.Seed(1)
+	for i := 0; i < 64; i++ {
+		isa64.Bool()
+	}
+	ref64.Uint64()
+	if n, v := isa64.Uint64(), ref64.Uint64(); n != v {
+		t.Fatalf("%x expected but found %x", v, n)
+	}
+
+	// Seeding drops the buffer.
+	isa64.Bool()
+	isa64.Seed(1)
+	ref64.Seed(1)
+	if n, v := isa64.Bits(64), ref64.Uint64(); n != v {
+		t.Fatalf("%x expected but found %x", v, n)
+	}
+}
+
+func TestBernoulli(t *testing.T) {
+	isa, ref := NewIsaac64(), NewIsaac64()
+	isa.Seed(1)
+	ref.Seed(1)
+
+	// p = 1/2 takes exactly one bit and is true when it is 0.
+	for i := 0; i < 200; i++ {
+		if n, v := isa.Bernoulli(0.5), ref.Bits(1) == 0; n != v {
+			t.Fatalf("[%v] %v expected but found %v", i, v, n)
+		}
+	}
+
+	// Certain outcomes take no bits.
+	if isa.Bernoulli(0) || isa.Bernoulli(-1) || !isa.Bernoulli(1) || !isa.Bernoulli(2) {
+		t.Fatal("wrong certain outcome")
+	}
+	if n, v := isa.Bits(64), ref.Bits(64); n != v {
+		t.Fatalf("%x expected but found %x", v, n)
+	}
+
+	// The frequency matches p, using two bits per draw on average.
+	var r bitReservoir
+	words := 0
+	word := func() uint64 {
+		words++
+		return isa.Uint64()
+	}
+	hits := 0
+	const draws = 100000
+	for i := 0; i < draws; i++ {
+		if r.bernoulli(0.3, word, 64) {
+			hits++
+		}
+	}
+	if f := float64(hits) / draws; f < 0.294 || f > 0.306 {
+		t.Fatalf("frequency %v too far from 0.3", f)
+	}
+	if bits := float64(64*words-int(r.n)) / draws; bits < 1.95 || bits > 2.05 {
+		t.Fatalf("%v bits per draw", bits)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic on NaN")
+			}
+		}()
+		zero := 0.0
+		isa.Bernoulli(zero / zero)
+	}()
+}
+
+func BenchmarkIsaacBool(b *testing.B) {
+	isa := NewIsaac()
+	isa.Seed(1)
+
+	for i := 0; i < b.N; i++ {
+		sinkBool = isa.Bool()
+	}
+}
+
+func BenchmarkIsaac64Bool(b *testing.B) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+
+	for i := 0; i < b.N; i++ {
+		sinkBool = isa.Bool()
+	}
+}
+
+var sinkBool bool
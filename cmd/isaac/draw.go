@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/skdltmxn/go-isaac/draw"
+)
+
+// readEntries reads one entry per line, skipping blank lines.
+func readEntries(r io.Reader) ([]string, error) {
+	var entries []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			entries = append(entries, line)
+		}
+	}
+
+	return entries, sc.Err()
+}
+
+func verifyDraw(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("isaac verify-draw", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	entriesPath := fs.String("entries", "", "read the entries of the draw from `file`, one per line")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *entriesPath == "" {
+		return errors.New("-entries is required")
+	}
+	if fs.NArg() != 1 {
+		return errors.New("expected a single record file")
+	}
+
+	b, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var rec draw.Record
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return fmt.Errorf("%s: %v", fs.Arg(0), err)
+	}
+
+	f, err := os.Open(*entriesPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entries, err := readEntries(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", *entriesPath, err)
+	}
+
+	if err := draw.Verify(&rec, entries); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "ok: %v winners from %v entries match\n", len(rec.Winners), rec.Entries)
+	return nil
+}
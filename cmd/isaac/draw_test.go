@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skdltmxn/go-isaac/draw"
+)
+
+func TestVerifyDraw(t *testing.T) {
+	entries := []string{"alice", "bob", "carol", "dave", "erin"}
+	rec, err := draw.Draw(entries, "published value", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	recPath, entriesPath := filepath.Join(dir, "record.json"), filepath.Join(dir, "entries.txt")
+	if err := os.WriteFile(recPath, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(entriesPath, []byte("erin\n\nbob\n  alice\ncarol\r\ndave\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runCmd(t, "verify-draw", "-entries", entriesPath, recPath)
+	if string(out) != "ok: 2 winners from 5 entries match\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+
+	// A record naming another winner is rejected.
+	other := entries[0]
+	if rec.Winners[0].Entry == other {
+		other = entries[1]
+	}
+	b = bytes.Replace(b, []byte(`"entry":"`+rec.Winners[0].Entry+`"`), []byte(`"entry":"`+other+`"`), 1)
+	if err := os.WriteFile(recPath, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = run([]string{"verify-draw", "-entries", entriesPath, recPath}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("expected mismatch, got %v", err)
+	}
+}
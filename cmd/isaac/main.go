@@ -0,0 +1,58 @@
+// Copyright 2021 skdltmxn. All rights reserved.
+//
+// main.go
+//
+// Command isaac writes ISAAC output to stdout for external test batteries.
+//
+// Usage:
+//
+//	isaac [flags]
+//	isaac vectors [-verify file] [flags]
+//	isaac verify-draw -entries file record.json
+//
+// The stream is endless unless -n is given, so it can be piped directly into
+// tools such as PractRand (RNG_test stdin32) or dieharder (-g 200).
+//
+// The vectors subcommand prints output in the format of the reference
+// randvect.txt for any seed, or checks a reference file with -verify.
+//
+// The verify-draw subcommand checks the JSON audit record of a prize draw made
+// with package draw against the entries of the draw, one per line.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, syscall.EPIPE) {
+			return
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+
+		fmt.Fprintln(os.Stderr, "isaac:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "vectors":
+			return vectors(args[1:], stdout, stderr)
+		case "verify-draw":
+			return verifyDraw(args[1:], stdout, stderr)
+		}
+	}
+
+	return stream(args, stdout, stderr)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+type seedFlags struct {
+	int    int64
+	hex    string
+	string string
+	file   string
+	alg    string
+}
+
+func (s *seedFlags) register(fs *flag.FlagSet) {
+	fs.Int64Var(&s.int, "seed", 0, "seed with a 64-bit integer")
+	fs.StringVar(&s.hex, "hex", "", "seed with hex encoded bytes")
+	fs.StringVar(&s.string, "string", "", "seed with a string")
+	fs.StringVar(&s.file, "file", "", "seed with the contents of a file")
+	fs.StringVar(&s.alg, "alg", "isaac", "algorithm: isaac or isaac64")
+}
+
+type seeder interface {
+	Seed(seed int64)
+	SeedBytes(seed []byte)
+}
+
+// source returns the name of the seed flag set in fs, or an empty string if
+// none was given.
+func (s *seedFlags) source(fs *flag.FlagSet) (string, error) {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	name := ""
+	for _, n := range []string{"seed", "hex", "string", "file"} {
+		if !set[n] {
+			continue
+		}
+		if name != "" {
+			return "", errors.New("only one of -seed, -hex, -string and -file may be given")
+		}
+		name = n
+	}
+
+	return name, nil
+}
+
+// bytes returns the seed selected by the flags set in fs as a byte sequence.
+// An integer seed is encoded as 8 little-endian bytes. It reports false if
+// no seed flag was given.
+func (s *seedFlags) bytes(fs *flag.FlagSet) ([]byte, bool, error) {
+	name, err := s.source(fs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch name {
+	case "seed":
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(s.int))
+		return b, true, nil
+	case "hex":
+		b, err := hex.DecodeString(s.hex)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid -hex: %v", err)
+		}
+		return b, true, nil
+	case "string":
+		return []byte(s.string), true, nil
+	case "file":
+		b, err := os.ReadFile(s.file)
+		if err != nil {
+			return nil, false, err
+		}
+		return b, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// apply seeds ctx according to the flags set in fs. It reports false if no
+// seed flag was given.
+func (s *seedFlags) apply(fs *flag.FlagSet, ctx seeder) (bool, error) {
+	name, err := s.source(fs)
+	if err != nil {
+		return false, err
+	}
+	if name == "seed" {
+		ctx.Seed(s.int)
+		return true, nil
+	}
+
+	b, ok, err := s.bytes(fs)
+	if ok {
+		ctx.SeedBytes(b)
+	}
+
+	return ok, err
+}
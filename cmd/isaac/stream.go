@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+type generator struct {
+	size int
+	put  func(b []byte)
+}
+
+func newGenerator(fs *flag.FlagSet, s *seedFlags, order binary.ByteOrder) (*generator, error) {
+	switch s.alg {
+	case "isaac":
+		ctx := isaac.NewIsaac()
+		ok, err := s.apply(fs, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if ctx, err = isaac.NewIsaacFromEntropy(); err != nil {
+				return nil, err
+			}
+		}
+		return &generator{4, func(b []byte) { order.PutUint32(b, ctx.Uint32()) }}, nil
+
+	case "isaac64":
+		ctx := isaac.NewIsaac64()
+		ok, err := s.apply(fs, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if ctx, err = isaac.NewIsaac64FromEntropy(); err != nil {
+				return nil, err
+			}
+		}
+		return &generator{8, func(b []byte) { order.PutUint64(b, ctx.Uint64()) }}, nil
+	}
+
+	return nil, fmt.Errorf("unknown algorithm %q", s.alg)
+}
+
+func parseOrder(name string) (binary.ByteOrder, error) {
+	switch name {
+	case "little":
+		return binary.LittleEndian, nil
+	case "big":
+		return binary.BigEndian, nil
+	}
+
+	return nil, fmt.Errorf("unknown byte order %q", name)
+}
+
+func stream(args []string, stdout, stderr io.Writer) error {
+	var seed seedFlags
+	fs := flag.NewFlagSet("isaac", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	seed.register(fs)
+	orderName := fs.String("order", "little", "byte order of each word: little or big")
+	skip := fs.Uint64("skip", 0, "number of words to discard before writing")
+	length := fs.Uint64("n", 0, "number of bytes to write, 0 for an endless stream")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	order, err := parseOrder(*orderName)
+	if err != nil {
+		return err
+	}
+
+	gen, err := newGenerator(fs, &seed, order)
+	if err != nil {
+		return err
+	}
+
+	word := make([]byte, gen.size)
+	for i := uint64(0); i < *skip; i++ {
+		gen.put(word)
+	}
+
+	w := bufio.NewWriterSize(stdout, 64*1024)
+	for remain := *length; *length == 0 || remain > 0; {
+		gen.put(word)
+
+		n := uint64(len(word))
+		if *length != 0 && remain < n {
+			n = remain
+		}
+		if _, err := w.Write(word[:n]); err != nil {
+			return err
+		}
+		remain -= n
+	}
+
+	return w.Flush()
+}
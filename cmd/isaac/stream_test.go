@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+func runCmd(t *testing.T, args ...string) []byte {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	if err := run(args, &out, &errOut); err != nil {
+		t.Fatalf("%v: %v\n%s", args, err, errOut.String())
+	}
+
+	return out.Bytes()
+}
+
+func TestStreamIsaac(t *testing.T) {
+	ref := isaac.NewIsaac()
+	ref.Seed(42)
+
+	out := runCmd(t, "-seed", "42", "-n", "1026")
+	if len(out) != 1026 {
+		t.Fatalf("expected 1026 bytes, got %v", len(out))
+	}
+
+	for i := 0; i+4 <= len(out); i += 4 {
+		if v, n := ref.Uint32(), binary.LittleEndian.Uint32(out[i:]); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i/4, v, n)
+		}
+	}
+}
+
+func TestStreamIsaac64(t *testing.T) {
+	ref := isaac.NewIsaac64()
+	ref.SeedString("hello")
+	for i := 0; i < 3; i++ {
+		ref.Uint64()
+	}
+
+	out := runCmd(t, "-alg", "isaac64", "-string", "hello", "-order", "big", "-skip", "3", "-n", "80")
+	for i := 0; i < len(out); i += 8 {
+		if v, n := ref.Uint64(), binary.BigEndian.Uint64(out[i:]); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i/8, v, n)
+		}
+	}
+}
+
+func TestStreamSeedSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed")
+	if err := os.WriteFile(path, []byte("abc"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	hexOut := runCmd(t, "-hex", "616263", "-n", "64")
+	strOut := runCmd(t, "-string", "abc", "-n", "64")
+	fileOut := runCmd(t, "-file", path, "-n", "64")
+
+	if !bytes.Equal(hexOut, strOut) || !bytes.Equal(hexOut, fileOut) {
+		t.Fatal("seed sources with the same bytes produced different streams")
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	cases := [][]string{
+		{"-seed", "1", "-string", "x"},
+		{"-alg", "isaac32"},
+		{"-order", "middle"},
+		{"-hex", "zz"},
+		{"extra"},
+	}
+
+	for _, args := range cases {
+		var out, errOut bytes.Buffer
+		if err := run(args, &out, &errOut); err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+	}
+}
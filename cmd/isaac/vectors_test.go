@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyTestdata(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no vector files found")
+	}
+
+	for _, path := range paths {
+		out := runCmd(t, "vectors", "-verify", path)
+		if !strings.HasPrefix(string(out), "ok: 512 words match") {
+			t.Fatalf("%s: unexpected output %q", path, out)
+		}
+	}
+}
+
+func TestVectorsRoundTrip(t *testing.T) {
+	out := runCmd(t, "vectors", "-alg", "isaac64", "-string", "new seed", "-header")
+	if !bytes.HasPrefix(out, []byte("# alg: isaac64\n# seed: 6e65772073656564\n")) {
+		t.Fatalf("unexpected header %q", out[:40])
+	}
+
+	path := filepath.Join(t.TempDir(), "vect.txt")
+	if err := os.WriteFile(path, out, 0600); err != nil {
+		t.Fatal(err)
+	}
+	runCmd(t, "vectors", "-verify", path)
+
+	// The seed given on the command line overrides the header.
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"vectors", "-verify", path, "-string", "old seed"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected mismatch for a different seed")
+	}
+	if !strings.HasPrefix(stdout.String(), "block 0 word 0: expected ") {
+		t.Fatalf("unexpected diff output %q", stdout.String())
+	}
+}
+
+func TestVectorsFormat(t *testing.T) {
+	out := runCmd(t, "vectors")
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != 64 || lines[0] != "f650e4c8e448e96d98db2fb4f5fad54f433f1afbedec154ad837048746ca4f9a" {
+		t.Fatalf("unexpected randvect output: %v lines, first %q", len(lines), lines[0])
+	}
+
+	out = runCmd(t, "vectors", "-alg", "isaac64")
+	lines = strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != 128 || len(lines[0]) != 64 {
+		t.Fatalf("unexpected isaac64 output: %v lines, first %q", len(lines), lines[0])
+	}
+}
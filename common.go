@@ -1,16 +1,71 @@
 package isaac
 
-import "unsafe"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
 
 const (
 	uintMax  = 1 << 63
 	uintMask = uintMax - 1
 )
 
-func unsafeCopy(dst, src unsafe.Pointer, size int) {
-	for i := 0; i < size; i++ {
-		*(*uint8)(dst) = *(*uint8)(src)
-		dst = unsafe.Pointer(uintptr(dst) + unsafe.Sizeof(uint8(0)))
-		src = unsafe.Pointer(uintptr(src) + unsafe.Sizeof(uint8(0)))
+// MinSizeLog2 and MaxSizeLog2 bound the state size accepted by NewIsaacSize
+// and NewIsaac64Size, in log2 of words. Initialization mixes eight words at a
+// time, so the state holds at least 2^3 words.
+const (
+	MinSizeLog2 = 3
+	MaxSizeLog2 = 16
+)
+
+// defaultSizeLog2 is RANDSIZL of the reference implementation.
+const defaultSizeLog2 = 8
+
+var errWiped = errors.New("isaac: use of wiped generator")
+
+// wipeBytes overwrites b with zeros.
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// checkSizeLog2 panics if log2Words is out of the supported range.
+func checkSizeLog2(log2Words int) {
+	if log2Words < MinSizeLog2 || log2Words > MaxSizeLog2 {
+		panic(fmt.Sprintf("isaac: state size 2^%d out of range [2^%d, 2^%d]", log2Words, MinSizeLog2, MaxSizeLog2))
+	}
+}
+
+// Uint64n returns a uniform integer in [0, n) drawn from g. It returns v % n
+// for the first value v of g.Uint64 below 2^64 - 1 - (2^64 - 1) % n, which
+// rejects the values in the incomplete last range of n values. The method is
+// simple to repeat elsewhere, so outcomes derived from it can be audited
+// independently. It panics if n is 0.
+func Uint64n(g Generator, n uint64) uint64 {
+	if n == 0 {
+		panic("isaac: invalid argument to Uint64n")
+	}
+
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := g.Uint64(); v < limit {
+			return v % n
+		}
+	}
+}
+
+// Uint32n is Uint64n for values of g.Uint32. It panics if n is 0.
+func Uint32n(g Generator, n uint32) uint32 {
+	if n == 0 {
+		panic("isaac: invalid argument to Uint32n")
+	}
+
+	limit := math.MaxUint32 - math.MaxUint32%n
+	for {
+		if v := g.Uint32(); v < limit {
+			return v % n
+		}
 	}
 }
@@ -0,0 +1,66 @@
+package isaac
+
+import "testing"
+
+// words is a Generator returning fixed words, for checking which values are
+// rejected.
+type words struct {
+	Generator
+	v []uint64
+}
+
+func (w *words) Uint64() uint64 {
+	v := w.v[0]
+	w.v = w.v[1:]
+	return v
+}
+
+func (w *words) Uint32() uint32 {
+	return uint32(w.Uint64())
+}
+
+func TestUint64n(t *testing.T) {
+	// 2^64 - 1 - (2^64 - 1) % 3 is 2^64 - 1, so only the maximum is rejected.
+	w := &words{v: []uint64{1<<64 - 1, 1<<64 - 2, 7}}
+	if n := Uint64n(w, 3); n != (1<<64-2)%3 || len(w.v) != 1 {
+		t.Fatalf("Uint64n(3) = %v, %v words left", n, len(w.v))
+	}
+
+	// For n = 2^63 + 1 every value from 2^63 + 1 up is rejected.
+	w = &words{v: []uint64{1 << 63, 1<<63 + 1, 5}}
+	if n := Uint64n(w, 1<<63+1); n != 1<<63 || len(w.v) != 2 {
+		t.Fatalf("Uint64n(2^63+1) = %v, %v words left", n, len(w.v))
+	}
+	if n := Uint64n(w, 1<<63+1); n != 5 || len(w.v) != 0 {
+		t.Fatalf("Uint64n(2^63+1) = %v, %v words left", n, len(w.v))
+	}
+
+	w = &words{v: []uint64{1<<32 - 1, 1<<32 - 2}}
+	if n := Uint32n(w, 3); n != (1<<32-2)%3 || len(w.v) != 0 {
+		t.Fatalf("Uint32n(3) = %v, %v words left", n, len(w.v))
+	}
+
+	isa := NewIsaac64()
+	isa.Seed(1)
+	var counts [6]int
+	for i := 0; i < 60000; i++ {
+		counts[Uint64n(isa, 6)]++
+		counts[Uint32n(isa, 6)]++
+	}
+	for v, c := range counts {
+		if c < 19000 || c > 21000 {
+			t.Errorf("%v drawn %v times out of 120000", v, c)
+		}
+	}
+
+	for _, f := range []func(){func() { Uint64n(isa, 0) }, func() { Uint32n(isa, 0) }} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic for n = 0")
+				}
+			}()
+			f()
+		}()
+	}
+}
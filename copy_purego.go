@@ -0,0 +1,45 @@
+//go:build purego || !(386 || amd64 || arm || arm64 || loong64 || mips64le || mipsle || ppc64le || riscv64 || wasm)
+// +build purego !386,!amd64,!arm,!arm64,!loong64,!mips64le,!mipsle,!ppc64le,!riscv64,!wasm
+
+package isaac
+
+import "encoding/binary"
+
+const puregoBuild = true
+
+// copySeed32 copies seed into dst as little-endian words. A trailing
+// partial word only overwrites its low-order bytes, which matches the
+// memory copy done by the default build. Big-endian architectures always use
+// this file, so a seed gives the same stream on every platform.
+func copySeed32(dst []uint32, seed []byte) {
+	var buf [4]byte
+	for i := 0; i < len(dst) && len(seed) > 0; i++ {
+		if len(seed) < len(buf) {
+			binary.LittleEndian.PutUint32(buf[:], dst[i])
+			copy(buf[:], seed)
+			dst[i] = binary.LittleEndian.Uint32(buf[:])
+			return
+		}
+
+		dst[i] = binary.LittleEndian.Uint32(seed)
+		seed = seed[len(buf):]
+	}
+}
+
+// copySeed64 copies seed into dst as little-endian words. A trailing
+// partial word only overwrites its low-order bytes, which matches the
+// memory copy done by the default build on little-endian platforms.
+func copySeed64(dst []uint64, seed []byte) {
+	var buf [8]byte
+	for i := 0; i < len(dst) && len(seed) > 0; i++ {
+		if len(seed) < len(buf) {
+			binary.LittleEndian.PutUint64(buf[:], dst[i])
+			copy(buf[:], seed)
+			dst[i] = binary.LittleEndian.Uint64(buf[:])
+			return
+		}
+
+		dst[i] = binary.LittleEndian.Uint64(seed)
+		seed = seed[len(buf):]
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !purego && (386 || amd64 || arm || arm64 || loong64 || mips64le || mipsle || ppc64le || riscv64 || wasm)
+// +build !purego
+// +build 386 amd64 arm arm64 loong64 mips64le mipsle ppc64le riscv64 wasm
+
+package isaac
+
+import "unsafe"
+
+const puregoBuild = false
+
+// unsafeCopy copies size bytes from src to dst. Each address is computed
+// from the base pointer, so none ever points past the end of an allocation.
+func unsafeCopy(dst, src unsafe.Pointer, size int) {
+	for i := 0; i < size; i++ {
+		*(*uint8)(unsafe.Pointer(uintptr(dst) + uintptr(i))) = *(*uint8)(unsafe.Pointer(uintptr(src) + uintptr(i)))
+	}
+}
+
+// copySeed32 copies seed into the memory of dst as-is. This file is only
+// built for little-endian architectures, where this decodes seed as
+// little-endian words like copy_purego.go does.
+func copySeed32(dst []uint32, seed []byte) {
+	if len(seed) > len(dst)*4 {
+		seed = seed[:len(dst)*4]
+	}
+	if len(seed) == 0 {
+		return
+	}
+
+	unsafeCopy(unsafe.Pointer(&dst[0]), unsafe.Pointer(&seed[0]), len(seed))
+}
+
+// copySeed64 copies seed into the memory of dst as-is.
+func copySeed64(dst []uint64, seed []byte) {
+	if len(seed) > len(dst)*8 {
+		seed = seed[:len(dst)*8]
+	}
+	if len(seed) == 0 {
+		return
+	}
+
+	unsafeCopy(unsafe.Pointer(&dst[0]), unsafe.Pointer(&seed[0]), len(seed))
+}
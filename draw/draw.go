@@ -0,0 +1,180 @@
+// Package draw implements auditable prize draws on top of ISAAC64.
+//
+// A draw takes a list of entries and a public seed, such as a value
+// published after the entries closed. The entries are canonicalized by
+// trimming surrounding white space and sorting them bytewise; empty and
+// duplicate entries are rejected. The entries hash is the SHA-256 of the
+// canonical entries, each preceded by its length as 4 bytes big-endian.
+//
+// The generator is an ISAAC64 instance seeded by SeedFromKey of package
+// isaac with the public seed as key, the entries hash as salt and an info of
+// the label "go-isaac/draw/v1". Winners are picked by a Fisher-Yates shuffle
+// of the canonical entries from the first position up, stopped after the
+// number of winners. The swap position for position i is i + isaac.Uint64n
+// of n - i, that is v % (n - i) for the first Uint64 v below
+// 2^64 - 1 - (2^64 - 1) % (n - i), so that no entry is favoured. The first
+// winners of a draw do not depend on the number of winners drawn.
+//
+// The Record of a draw names the scheme it was drawn with and is enough to
+// check it again given the entries.
+package draw
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+// Scheme names the version of the draw scheme recorded in a Record.
+const Scheme = "go-isaac/draw/v1"
+
+var (
+	// ErrEntries is returned for an entry list that is empty or contains an
+	// empty or duplicate entry.
+	ErrEntries = errors.New("draw: invalid entries")
+
+	// ErrMismatch is returned by Verify when a record does not match the
+	// draw it describes.
+	ErrMismatch = errors.New("draw: record does not match draw")
+)
+
+// Winner is an entry picked by a draw.
+type Winner struct {
+	// Rank is the order in which the entry was picked, starting at 1.
+	Rank int `json:"rank"`
+
+	// Index is the position of the entry in the canonical entries.
+	Index int `json:"index"`
+
+	Entry string `json:"entry"`
+}
+
+// Record is the audit record of a draw. Its fields are encoded by
+// encoding/json in a fixed order and contain no maps or floating-point
+// values, so the output of json.Marshal is stable and can be signed as is.
+type Record struct {
+	Scheme      string   `json:"scheme"`
+	PublicSeed  string   `json:"public_seed"`
+	Entries     int      `json:"entries"`
+	EntriesHash string   `json:"entries_sha256"`
+	Winners     []Winner `json:"winners"`
+}
+
+// Canonical returns the canonical form of entries, trimmed and sorted. It
+// returns ErrEntries if entries is empty or contains an empty or duplicate
+// entry once trimmed.
+func Canonical(entries []string) ([]string, error) {
+	if len(entries) == 0 {
+		return nil, ErrEntries
+	}
+
+	c := make([]string, len(entries))
+	for i, e := range entries {
+		c[i] = strings.TrimSpace(e)
+		if c[i] == "" {
+			return nil, fmt.Errorf("%w: entry %d is empty", ErrEntries, i)
+		}
+	}
+
+	sort.Strings(c)
+	for i := 1; i < len(c); i++ {
+		if c[i] == c[i-1] {
+			return nil, fmt.Errorf("%w: duplicate entry %q", ErrEntries, c[i])
+		}
+	}
+
+	return c, nil
+}
+
+// Hash returns the entries hash of canonical entries.
+func Hash(canonical []string) []byte {
+	h := sha256.New()
+	var n [4]byte
+	for _, e := range canonical {
+		binary.BigEndian.PutUint32(n[:], uint32(len(e)))
+		h.Write(n[:])
+		h.Write([]byte(e))
+	}
+
+	return h.Sum(nil)
+}
+
+// Generator returns the ISAAC64 instance a draw of canonical entries with the
+// given public seed picks its winners from.
+func Generator(canonical []string, publicSeed string) *isaac.Isaac64 {
+	g := isaac.NewIsaac64()
+	g.SeedFromKey([]byte(publicSeed), Hash(canonical), &isaac.KeyOptions{Info: []byte(Scheme)})
+	return g
+}
+
+// Draw picks winners entries without replacement and returns the record of
+// the draw. It returns ErrEntries for invalid entries, and an error if
+// winners is not in [1, number of entries].
+func Draw(entries []string, publicSeed string, winners int) (*Record, error) {
+	c, err := Canonical(entries)
+	if err != nil {
+		return nil, err
+	}
+	if winners < 1 || winners > len(c) {
+		return nil, fmt.Errorf("draw: cannot pick %d winners from %d entries", winners, len(c))
+	}
+
+	g := Generator(c, publicSeed)
+	idx := make([]int, len(c))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	r := &Record{
+		Scheme:      Scheme,
+		PublicSeed:  publicSeed,
+		Entries:     len(c),
+		EntriesHash: hex.EncodeToString(Hash(c)),
+		Winners:     make([]Winner, winners),
+	}
+	for i := range r.Winners {
+		j := i + int(isaac.Uint64n(g, uint64(len(c)-i)))
+		idx[i], idx[j] = idx[j], idx[i]
+		r.Winners[i] = Winner{Rank: i + 1, Index: idx[i], Entry: c[idx[i]]}
+	}
+
+	return r, nil
+}
+
+// Verify repeats the draw described by r with entries and checks that r
+// matches it. It returns ErrMismatch, wrapped with the first difference
+// found, if it does not.
+func Verify(r *Record, entries []string) error {
+	if r.Scheme != Scheme {
+		return fmt.Errorf("draw: unsupported scheme %q", r.Scheme)
+	}
+
+	c, err := Canonical(entries)
+	if err != nil {
+		return err
+	}
+	if r.Entries != len(c) {
+		return fmt.Errorf("%w: %d entries recorded, %d given", ErrMismatch, r.Entries, len(c))
+	}
+	if h := hex.EncodeToString(Hash(c)); r.EntriesHash != h {
+		return fmt.Errorf("%w: entries hash %s recorded, %s given", ErrMismatch, r.EntriesHash, h)
+	}
+
+	want, err := Draw(c, r.PublicSeed, len(r.Winners))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMismatch, err)
+	}
+	for i, w := range want.Winners {
+		if r.Winners[i] != w {
+			return fmt.Errorf("%w: winner %d is %+v, draw picked %+v", ErrMismatch, i+1, r.Winners[i], w)
+		}
+	}
+
+	return nil
+}
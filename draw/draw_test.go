@@ -0,0 +1,121 @@
+package draw
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+var people = []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi"}
+
+// TestDrawVector pins the scheme, so that records made by older versions
+// still verify.
+func TestDrawVector(t *testing.T) {
+	r, err := Draw(people, "published value", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"scheme":"go-isaac/draw/v1","public_seed":"published value","entries":8,` +
+		`"entries_sha256":"d8899c14d85e5847be19edd4b80297d8bb2c01920ecc402add012ec8d3b6165e",` +
+		`"winners":[{"rank":1,"index":6,"entry":"grace"},{"rank":2,"index":4,"entry":"erin"},` +
+		`{"rank":3,"index":0,"entry":"alice"}]}`
+	if string(b) != want {
+		t.Fatalf("unexpected record\n%s\nexpected\n%s", b, want)
+	}
+}
+
+func TestDrawCanonical(t *testing.T) {
+	r, err := Draw(people, "seed", 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Order and surrounding white space of the entries do not matter.
+	shuffled := []string{" heidi", "bob\t", "grace", "alice", "frank", "carol", "erin", "dave\n"}
+	if err := Verify(r, shuffled); err != nil {
+		t.Fatal(err)
+	}
+
+	// All entries are drawn once, and the first winners do not depend on
+	// the number of winners.
+	seen := make(map[string]bool)
+	for _, w := range r.Winners {
+		if seen[w.Entry] {
+			t.Fatalf("%q drawn twice", w.Entry)
+		}
+		seen[w.Entry] = true
+	}
+
+	r3, err := Draw(people, "seed", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, w := range r3.Winners {
+		if w != r.Winners[i] {
+			t.Fatalf("winner %v: %+v, drawing all picked %+v", i+1, w, r.Winners[i])
+		}
+	}
+}
+
+func TestDrawInvalid(t *testing.T) {
+	for _, entries := range [][]string{nil, {"a", " "}, {"a", "b", "a "}} {
+		if _, err := Draw(entries, "seed", 1); !errors.Is(err, ErrEntries) {
+			t.Errorf("%q: expected ErrEntries, got %v", entries, err)
+		}
+	}
+
+	for _, n := range []int{0, 9} {
+		if _, err := Draw(people, "seed", n); err == nil {
+			t.Errorf("%v winners: expected error", n)
+		}
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	tamper := []func(r *Record) []string{
+		func(r *Record) []string { r.PublicSeed += "!"; return people },
+		func(r *Record) []string { r.Winners[1].Entry = "mallory"; return people },
+		func(r *Record) []string { r.Winners[0].Index++; return people },
+		func(r *Record) []string { r.Winners = append(r.Winners, Winner{4, 1, "bob"}); return people },
+		func(r *Record) []string { return append([]string{"mallory"}, people...) },
+		func(r *Record) []string { return append([]string{"mallory"}, people[1:]...) },
+	}
+
+	for i, f := range tamper {
+		r, err := Draw(people, "seed", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(r, f(r)); !errors.Is(err, ErrMismatch) {
+			t.Errorf("case %v: expected ErrMismatch, got %v", i, err)
+		}
+	}
+}
+
+// TestDrawUniform checks that every entry is picked first about equally
+// often over many public seeds.
+func TestDrawUniform(t *testing.T) {
+	const runs = 8000
+
+	counts := make(map[string]int)
+	for i := 0; i < runs; i++ {
+		r, err := Draw(people, strconv.Itoa(i), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[r.Winners[0].Entry]++
+	}
+
+	for _, p := range people {
+		if c := counts[p]; c < runs/8*8/10 || c > runs/8*12/10 {
+			t.Errorf("%q picked first %v times out of %v", p, c, runs)
+		}
+	}
+}
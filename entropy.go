@@ -0,0 +1,112 @@
+package isaac
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// NewIsaacFromEntropy returns a new instance of ISAAC whose whole seed is
+// read from the operating system's secure random number generator. It should
+// be preferred over Seed whenever the output leaves the process.
+func NewIsaacFromEntropy() (*Isaac, error) {
+	ctx := NewIsaac()
+	if err := ctx.seedFrom(rand.Reader); err != nil {
+		return nil, err
+	}
+
+	return ctx, nil
+}
+
+// NewIsaac64FromEntropy returns a new instance of ISAAC64 whose whole seed is
+// read from the operating system's secure random number generator. It should
+// be preferred over Seed whenever the output leaves the process.
+func NewIsaac64FromEntropy() (*Isaac64, error) {
+	ctx := NewIsaac64()
+	if err := ctx.seedFrom(rand.Reader); err != nil {
+		return nil, err
+	}
+
+	return ctx, nil
+}
+
+// Reseed mixes fresh entropy from the operating system into the current state
+// of ISAAC instance. The state is left untouched if reading entropy fails.
+func (ctx *Isaac) Reseed() error {
+	return ctx.reseedFrom(rand.Reader)
+}
+
+// Reseed mixes fresh entropy from the operating system into the current state
+// of ISAAC64 instance. The state is left untouched if reading entropy fails.
+func (ctx *Isaac64) Reseed() error {
+	return ctx.reseedFrom(rand.Reader)
+}
+
+func (ctx *Isaac) seedFrom(r io.Reader) error {
+	buf := make([]byte, ctx.seedSize())
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+
+	ctx.SeedBytes(buf)
+	wipeBytes(buf)
+	return nil
+}
+
+func (ctx *Isaac64) seedFrom(r io.Reader) error {
+	buf := make([]byte, ctx.seedSize())
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+
+	ctx.SeedBytes(buf)
+	wipeBytes(buf)
+	return nil
+}
+
+func (ctx *Isaac) reseedFrom(r io.Reader) error {
+	buf := make([]byte, ctx.seedSize())
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+
+	rsl, mem := ctx.rsl(), ctx.mem()
+	fresh := make([]uint32, len(rsl))
+	copySeed32(fresh, buf)
+	wipeBytes(buf)
+	for i := range rsl {
+		rsl[i] ^= mem[i] ^ fresh[i]
+		fresh[i] = 0
+	}
+
+	ctx.randInit(true)
+	return nil
+}
+
+func (ctx *Isaac64) reseedFrom(r io.Reader) error {
+	buf := make([]byte, ctx.seedSize())
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+
+	rsl, mem := ctx.rsl(), ctx.mem()
+	fresh := make([]uint64, len(rsl))
+	copySeed64(fresh, buf)
+	wipeBytes(buf)
+	for i := range rsl {
+		rsl[i] ^= mem[i] ^ fresh[i]
+		fresh[i] = 0
+	}
+
+	ctx.randInit(true)
+	return nil
+}
+
+// seedSize returns the size of a full seed in bytes.
+func (ctx *Isaac) seedSize() int {
+	return 4 * len(ctx.rsl())
+}
+
+// seedSize returns the size of a full seed in bytes.
+func (ctx *Isaac64) seedSize() int {
+	return 8 * len(ctx.rsl())
+}
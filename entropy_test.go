@@ -0,0 +1,139 @@
+package isaac
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+func TestEntropyConstructors(t *testing.T) {
+	a, err := NewIsaacFromEntropy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewIsaacFromEntropy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.randrsl == b.randrsl {
+		t.Fatal("two entropy seeded ISAAC instances produced the same block")
+	}
+
+	a64, err := NewIsaac64FromEntropy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64, err := NewIsaac64FromEntropy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a64.randrsl == b64.randrsl {
+		t.Fatal("two entropy seeded ISAAC64 instances produced the same block")
+	}
+}
+
+func TestSeedFromReader(t *testing.T) {
+	seed := bytes.Repeat([]byte{0xa5}, 2048)
+
+	isa := NewIsaac()
+	if err := isa.seedFrom(bytes.NewReader(seed)); err != nil {
+		t.Fatal(err)
+	}
+	ref := NewIsaac()
+	ref.SeedBytes(seed)
+	if isa.Uint32() != ref.Uint32() {
+		t.Fatal("seedFrom differs from SeedBytes")
+	}
+
+	isa64 := NewIsaac64()
+	if err := isa64.seedFrom(bytes.NewReader(seed)); err != nil {
+		t.Fatal(err)
+	}
+	ref64 := NewIsaac64()
+	ref64.SeedBytes(seed)
+	if isa64.Uint64() != ref64.Uint64() {
+		t.Fatal("seedFrom differs from SeedBytes")
+	}
+}
+
+func TestReseed(t *testing.T) {
+	isa := NewIsaac()
+	isa.Seed(1)
+	ref := *isa
+
+	if err := isa.reseedFrom(errReader{}); err == nil {
+		t.Fatal("expected error from failing reader")
+	}
+	if *isa != ref {
+		t.Fatal("failed reseed modified the state")
+	}
+
+	zero := make([]byte, 1024)
+	if err := isa.reseedFrom(bytes.NewReader(zero)); err != nil {
+		t.Fatal(err)
+	}
+	if isa.randrsl == ref.randrsl {
+		t.Fatal("reseed did not change the state")
+	}
+	if err := isa.Reseed(); err != nil {
+		t.Fatal(err)
+	}
+
+	isa64 := NewIsaac64()
+	isa64.Seed(1)
+	ref64 := *isa64
+
+	if err := isa64.reseedFrom(errReader{}); err == nil {
+		t.Fatal("expected error from failing reader")
+	}
+	if *isa64 != ref64 {
+		t.Fatal("failed reseed modified the state")
+	}
+	if err := isa64.Reseed(); err != nil {
+		t.Fatal(err)
+	}
+	if isa64.randrsl == ref64.randrsl {
+		t.Fatal("reseed did not change the state")
+	}
+}
+
+// TestReseedZeroValue checks that Reseed of a zero value mixes in a whole seed
+// of entropy, as for an instance made by NewIsaac.
+func TestReseedZeroValue(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x5a}, 2048)
+
+	var isa Isaac
+	if err := isa.reseedFrom(bytes.NewReader(entropy)); err != nil {
+		t.Fatal(err)
+	}
+	ref, zero := NewIsaac(), NewIsaac()
+	ref.reseedFrom(bytes.NewReader(entropy))
+	zero.Seed(0)
+	if isa != *ref || isa.randrsl == zero.randrsl {
+		t.Fatal("zero value reseeded without the entropy")
+	}
+
+	var isa64 Isaac64
+	if err := isa64.reseedFrom(bytes.NewReader(entropy)); err != nil {
+		t.Fatal(err)
+	}
+	ref64, zero64 := NewIsaac64(), NewIsaac64()
+	ref64.reseedFrom(bytes.NewReader(entropy))
+	zero64.Seed(0)
+	if isa64 != *ref64 || isa64.randrsl == zero64.randrsl {
+		t.Fatal("zero value reseeded without the entropy")
+	}
+
+	var a, b Isaac
+	a.Reseed()
+	b.Reseed()
+	if a.randrsl == b.randrsl {
+		t.Fatal("two zero values reseeded to the same state")
+	}
+}
@@ -0,0 +1,142 @@
+// Package fair implements a provably fair commit/reveal scheme on top of
+// ISAAC.
+//
+// Before play the server draws a secret server seed and publishes its
+// commitment, the hex encoded SHA-256 of the seed. Each round is then played
+// with an ISAAC instance derived from the server seed, a client seed chosen
+// by the player and a nonce counting the rounds. Once the server seed is
+// revealed, anyone can check it against the commitment with Verify and
+// replay every round with the same outcome helpers.
+//
+// The derivation is SeedFromKey of package isaac with the server seed as key,
+// no salt, and an info of the label "go-isaac/fair/v1", the length of the
+// client seed as 4 bytes big-endian, the client seed and the nonce as 8 bytes
+// big-endian. Players may check a round long after it was played, so a
+// change to the derivation would come with a new label rather than alter the
+// rounds seeded under this one.
+package fair
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+const label = "go-isaac/fair/v1"
+
+// ServerSeedSize is the size of server seeds made by NewServerSeed.
+const ServerSeedSize = 32
+
+// ErrCommitment is returned by Verify when the revealed server seed does not
+// match the commitment.
+var ErrCommitment = errors.New("fair: server seed does not match commitment")
+
+// NewServerSeed returns a new secret server seed read from the operating
+// system's secure random number generator.
+func NewServerSeed() ([]byte, error) {
+	seed := make([]byte, ServerSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+
+	return seed, nil
+}
+
+// Commit returns the commitment to serverSeed to be published before play.
+func Commit(serverSeed []byte) string {
+	sum := sha256.Sum256(serverSeed)
+	return hex.EncodeToString(sum[:])
+}
+
+// Round identifies a single round of play.
+type Round struct {
+	ServerSeed []byte
+	ClientSeed string
+	Nonce      uint64
+}
+
+// Generator returns the ISAAC instance all outcomes of the round are derived
+// from.
+func (r Round) Generator() *isaac.Isaac {
+	info := make([]byte, len(label)+4+len(r.ClientSeed)+8)
+	n := copy(info, label)
+	binary.BigEndian.PutUint32(info[n:], uint32(len(r.ClientSeed)))
+	n += 4 + copy(info[n+4:], r.ClientSeed)
+	binary.BigEndian.PutUint64(info[n:], r.Nonce)
+
+	g := isaac.NewIsaac()
+	g.SeedFromKey(r.ServerSeed, nil, &isaac.KeyOptions{Info: info})
+	return g
+}
+
+// Verify checks the revealed server seed of r against commitment and returns
+// the generator of the round, from which the outcomes can be derived again
+// with the same helpers the server used.
+func Verify(commitment string, r Round) (*isaac.Isaac, error) {
+	want, err := hex.DecodeString(commitment)
+	if err != nil || len(want) != sha256.Size {
+		return nil, ErrCommitment
+	}
+
+	sum := sha256.Sum256(r.ServerSeed)
+	if subtle.ConstantTimeCompare(sum[:], want) != 1 {
+		return nil, ErrCommitment
+	}
+
+	return r.Generator(), nil
+}
+
+// Dice returns the roll of a die with the given number of sides, 1 +
+// isaac.Uint32n of sides. It panics if sides is not in [1, 2^32 - 1].
+func Dice(g *isaac.Isaac, sides int) int {
+	if sides < 1 || uint64(sides) > math.MaxUint32 {
+		panic("fair: invalid number of sides")
+	}
+
+	return int(isaac.Uint32n(g, uint32(sides))) + 1
+}
+
+// Cards returns the order of a deck of n cards, a permutation of [0, n), by
+// a Fisher-Yates shuffle from the last card down that swaps card i with card
+// isaac.Uint32n of i + 1. It panics if n is not in [0, 2^32 - 1].
+func Cards(g *isaac.Isaac, n int) []int {
+	if n < 0 || uint64(n) > math.MaxUint32 {
+		panic("fair: invalid number of cards")
+	}
+
+	deck := make([]int, n)
+	for i := range deck {
+		deck[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := int(isaac.Uint32n(g, uint32(i+1)))
+		deck[i], deck[j] = deck[j], deck[i]
+	}
+
+	return deck
+}
+
+// Crash returns the multiplier at which a crash game ends, at least 1 and
+// rounded down to hundredths. With u = (Uint64 >> 11) / 2^53 uniform in
+// [0, 1), the multiplier is (1 - houseEdge) / (1 - u), so that a bet cashed
+// out at x pays back 1 - houseEdge of the stake on average. It panics if
+// houseEdge is not in [0, 1).
+func Crash(g *isaac.Isaac, houseEdge float64) float64 {
+	if !(houseEdge >= 0 && houseEdge < 1) {
+		panic("fair: invalid house edge")
+	}
+
+	u := float64(g.Uint64()>>11) / (1 << 53)
+	m := math.Floor(100*(1-houseEdge)/(1-u)) / 100
+	if m < 1 {
+		return 1
+	}
+
+	return m
+}
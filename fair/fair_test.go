@@ -0,0 +1,174 @@
+package fair
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestFair(t *testing.T) {
+	// Server: commit to a seed and play a few rounds.
+	seed, err := NewServerSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := Commit(seed)
+
+	type result struct {
+		dice  int
+		cards []int
+		crash float64
+	}
+	play := func(r Round) result {
+		g := r.Generator()
+		return result{dice: Dice(g, 6), cards: Cards(g, 52), crash: Crash(g, 0.01)}
+	}
+
+	var played []result
+	for nonce := uint64(0); nonce < 10; nonce++ {
+		played = append(played, play(Round{ServerSeed: seed, ClientSeed: "player", Nonce: nonce}))
+	}
+
+	// Player: verify the revealed seed and replay every round.
+	for nonce, want := range played {
+		r := Round{ServerSeed: seed, ClientSeed: "player", Nonce: uint64(nonce)}
+		g, err := Verify(commitment, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := Dice(g, 6); d != want.dice {
+			t.Fatalf("round %v: dice %v, server rolled %v", nonce, d, want.dice)
+		}
+		cards := Cards(g, 52)
+		for i := range cards {
+			if cards[i] != want.cards[i] {
+				t.Fatalf("round %v: card order differs", nonce)
+			}
+		}
+		if c := Crash(g, 0.01); c != want.crash {
+			t.Fatalf("round %v: crash %v, server had %v", nonce, c, want.crash)
+		}
+	}
+
+	// A different seed does not match the commitment.
+	other := append([]byte(nil), seed...)
+	other[0] ^= 1
+	if _, err := Verify(commitment, Round{ServerSeed: other}); err != ErrCommitment {
+		t.Fatalf("expected %v, got %v", ErrCommitment, err)
+	}
+	if _, err := Verify("not hex", Round{ServerSeed: seed}); err != ErrCommitment {
+		t.Fatalf("expected %v, got %v", ErrCommitment, err)
+	}
+}
+
+// TestVectors pins the outcomes of a round, which must not change between
+// versions for already published games to stay verifiable.
+func TestVectors(t *testing.T) {
+	g := Round{ServerSeed: []byte("server seed"), ClientSeed: "player", Nonce: 7}.Generator()
+	if d := Dice(g, 6); d != 6 {
+		t.Fatalf("dice 6 expected but found %v", d)
+	}
+	want := []int{6, 4, 8, 2, 1, 9, 7, 5, 3, 0}
+	for i, c := range Cards(g, 10) {
+		if c != want[i] {
+			t.Fatalf("cards %v expected but found %v", want, Cards(g, 10))
+		}
+	}
+	if c := Crash(g, 0.01); c != 6.07 {
+		t.Fatalf("crash 6.07 expected but found %v", c)
+	}
+}
+
+func TestDerivation(t *testing.T) {
+	seed := []byte("server seed")
+	if c := Commit(seed); c != "a4e53dc2f480b8fce6fe688b1317658b446299df23ad533394406427c8c19557" {
+		t.Fatalf("unexpected commitment %v", c)
+	}
+
+	// Client seed and nonce are framed, so shifting bytes between them
+	// changes the round.
+	a := Round{ServerSeed: seed, ClientSeed: "ab", Nonce: 1}.Generator().Uint64()
+	b := Round{ServerSeed: seed, ClientSeed: "a", Nonce: 1}.Generator().Uint64()
+	c := Round{ServerSeed: seed, ClientSeed: "ab", Nonce: 2}.Generator().Uint64()
+	if a == b || a == c {
+		t.Fatal("rounds are not independent")
+	}
+}
+
+func TestDice(t *testing.T) {
+	g := Round{ServerSeed: []byte("dice")}.Generator()
+	count := make([]int, 7)
+	for i := 0; i < 6000; i++ {
+		count[Dice(g, 6)]++
+	}
+	if count[0] != 0 {
+		t.Fatal("rolled 0")
+	}
+	for v, c := range count[1:] {
+		if c < 850 || c > 1150 {
+			t.Fatalf("%v rolled %v times out of 6000", v+1, c)
+		}
+	}
+	if Dice(g, 1) != 1 {
+		t.Fatal("one-sided die")
+	}
+}
+
+func TestCards(t *testing.T) {
+	g := Round{ServerSeed: []byte("cards")}.Generator()
+	for _, n := range []int{0, 1, 52} {
+		deck := Cards(g, n)
+		sorted := append([]int(nil), deck...)
+		sort.Ints(sorted)
+		for i, v := range sorted {
+			if v != i {
+				t.Fatalf("%v: not a permutation: %v", n, deck)
+			}
+		}
+	}
+}
+
+func TestInvalidSizes(t *testing.T) {
+	g := Round{ServerSeed: []byte("sizes")}.Generator()
+	calls := map[string]func(){
+		"Dice(0)":   func() { Dice(g, 0) },
+		"Cards(-1)": func() { Cards(g, -1) },
+	}
+	// Sizes past 2^32 - 1 would be truncated to 32 bits, and only exist where
+	// int has 64 bits.
+	if max := int(^uint(0) >> 1); uint64(max) > math.MaxUint32 {
+		calls["Dice(2^32+6)"] = func() { Dice(g, max>>31+7) }
+		calls["Cards(max)"] = func() { Cards(g, max) }
+	}
+
+	for name, f := range calls {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected panic for %s", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestCrash(t *testing.T) {
+	g := Round{ServerSeed: []byte("crash")}.Generator()
+	const rounds = 100000
+	over2 := 0
+	for i := 0; i < rounds; i++ {
+		m := Crash(g, 0.01)
+		if m < 1 || math.Abs(m*100-math.Round(m*100)) > 1e-6 {
+			t.Fatalf("invalid multiplier %v", m)
+		}
+		if m >= 2 {
+			over2++
+		}
+	}
+
+	// P(m >= 2) = (1 - edge) / 2
+	if f := float64(over2) / rounds; f < 0.485 || f > 0.505 {
+		t.Fatalf("P(m >= 2) = %v", f)
+	}
+}
@@ -0,0 +1,242 @@
+package isaac
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzSeedBytes(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("This is <i>not</i> the right mytext.\x00"))
+
+	f.Fuzz(func(t *testing.T, seed []byte) {
+		orig := append([]byte(nil), seed...)
+
+		a := NewIsaac()
+		a.SeedBytes(seed)
+		if !bytes.Equal(seed, orig) {
+			t.Fatal("SeedBytes modified the seed")
+		}
+
+		b := NewIsaac()
+		b.SeedString(string(seed))
+		if a.randrsl != b.randrsl || a.randmem != b.randmem {
+			t.Fatal("SeedBytes and SeedString disagree")
+		}
+
+		for i := 0; i < 300; i++ {
+			if x, y := a.Uint32(), b.Uint32(); x != y {
+				t.Fatalf("[%v] %x != %x", i, x, y)
+			}
+		}
+
+		// Seeding a used instance must not depend on its previous state.
+		a.SeedBytes(seed)
+		c := NewIsaac()
+		c.SeedBytes(seed)
+		if a.randrsl != c.randrsl || a.randmem != c.randmem {
+			t.Fatal("SeedBytes depends on the previous state")
+		}
+	})
+}
+
+func FuzzIsaac64SeedBytes(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("This is <i>not</i> the right mytext.\x00"))
+
+	f.Fuzz(func(t *testing.T, seed []byte) {
+		orig := append([]byte(nil), seed...)
+
+		a := NewIsaac64()
+		a.SeedBytes(seed)
+		if !bytes.Equal(seed, orig) {
+			t.Fatal("SeedBytes modified the seed")
+		}
+
+		b := NewIsaac64()
+		b.SeedString(string(seed))
+		if a.randrsl != b.randrsl || a.randmem != b.randmem {
+			t.Fatal("SeedBytes and SeedString disagree")
+		}
+
+		for i := 0; i < 300; i++ {
+			if x, y := a.Uint64(), b.Uint64(); x != y {
+				t.Fatalf("[%v] %x != %x", i, x, y)
+			}
+		}
+
+		// Seeding a used instance must not depend on its previous state.
+		a.SeedBytes(seed)
+		c := NewIsaac64()
+		c.SeedBytes(seed)
+		if a.randrsl != c.randrsl || a.randmem != c.randmem {
+			t.Fatal("SeedBytes depends on the previous state")
+		}
+	})
+}
+
+func FuzzSeedFromKey(f *testing.F) {
+	f.Add([]byte(""), []byte(""), uint8(0))
+	f.Add([]byte("hunter2"), []byte("room-42"), uint8(3))
+
+	f.Fuzz(func(t *testing.T, key, salt []byte, iter uint8) {
+		opts := &KeyOptions{Iterations: int(iter)}
+
+		a, b := NewIsaac(), NewIsaac()
+		a.SeedFromKey(key, salt, opts)
+		b.SeedFromKey(key, salt, opts)
+		if a.randrsl != b.randrsl {
+			t.Fatal("SeedFromKey is not deterministic")
+		}
+
+		a64, b64 := NewIsaac64(), NewIsaac64()
+		a64.SeedFromKey(key, salt, opts)
+		b64.SeedFromKey(key, salt, opts)
+		if a64.randrsl != b64.randrsl {
+			t.Fatal("SeedFromKey is not deterministic")
+		}
+	})
+}
+
+func FuzzUnmarshalBinary(f *testing.F) {
+	for _, log2 := range []int{MinSizeLog2, defaultSizeLog2} {
+		isa := NewIsaacSize(log2)
+		isa.Seed(1)
+		isa.Uint32()
+		b, _ := isa.MarshalBinary()
+		f.Add(b)
+		f.Add(b[:len(b)-1])
+	}
+	f.Add([]byte{stateKind64, MinSizeLog2})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		isa := NewIsaac()
+		isa.Seed(2)
+		before, _ := isa.MarshalBinary()
+
+		if err := isa.UnmarshalBinary(data); err != nil {
+			if after, _ := isa.MarshalBinary(); !bytes.Equal(before, after) {
+				t.Fatal("failed UnmarshalBinary modified the state")
+			}
+			return
+		}
+
+		if after, _ := isa.MarshalBinary(); !bytes.Equal(data, after) {
+			t.Fatal("state does not round-trip")
+		}
+		for i := 0; i < 2*len(isa.rsl())+1; i++ {
+			isa.Uint32()
+		}
+	})
+}
+
+func FuzzIsaac64UnmarshalBinary(f *testing.F) {
+	for _, log2 := range []int{MinSizeLog2, defaultSizeLog2} {
+		isa := NewIsaac64Size(log2)
+		isa.Seed(1)
+		isa.Uint64()
+		b, _ := isa.MarshalBinary()
+		f.Add(b)
+		f.Add(b[:len(b)-1])
+	}
+	f.Add([]byte{stateKind32, MinSizeLog2})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		isa := NewIsaac64()
+		isa.Seed(2)
+		before, _ := isa.MarshalBinary()
+
+		if err := isa.UnmarshalBinary(data); err != nil {
+			if after, _ := isa.MarshalBinary(); !bytes.Equal(before, after) {
+				t.Fatal("failed UnmarshalBinary modified the state")
+			}
+			return
+		}
+
+		if after, _ := isa.MarshalBinary(); !bytes.Equal(data, after) {
+			t.Fatal("state does not round-trip")
+		}
+		for i := 0; i < 2*len(isa.rsl())+1; i++ {
+			isa.Uint64()
+		}
+	})
+}
+
+func FuzzCheckpointsUnmarshal(f *testing.F) {
+	isa := NewIsaacSize(MinSizeLog2)
+	isa.Seed(1)
+	s := NewSeekableIsaac(isa, 2)
+	s.Seek(40)
+	b, _ := s.Checkpoints().MarshalBinary()
+	f.Add(b)
+	f.Add(b[:len(b)/2])
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var cp Checkpoints
+		if err := cp.UnmarshalBinary(data); err != nil {
+			return
+		}
+
+		if b, _ := cp.MarshalBinary(); !bytes.Equal(data, b) {
+			t.Fatal("checkpoints do not round-trip")
+		}
+
+		switch cp.snaps[0][0] {
+		case stateKind32:
+			s, err := RestoreSeekableIsaac(&cp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Seek(uint64(cp.Len()) * 3)
+			s.Uint32()
+		case stateKind64:
+			s, err := RestoreSeekableIsaac64(&cp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Seek(uint64(cp.Len()) * 3)
+			s.Uint64()
+		}
+	})
+}
+
+func FuzzUint64n(f *testing.F) {
+	f.Add(int64(1), uint64(1), uint32(1))
+	f.Add(int64(7), uint64(1<<63+1), uint32(1<<31+1))
+
+	f.Fuzz(func(t *testing.T, seed int64, n uint64, m uint32) {
+		if n == 0 || m == 0 {
+			return
+		}
+
+		a, b := NewIsaac64(), NewIsaac64()
+		a.Seed(seed)
+		b.Seed(seed)
+		for i := 0; i < 4; i++ {
+			v := Uint64n(a, n)
+			if v >= n {
+				t.Fatalf("Uint64n(%v) = %v", n, v)
+			}
+
+			// The result is the first accepted value of the stream.
+			limit := ^uint64(0) - ^uint64(0)%n
+			w := b.Uint64()
+			for w >= limit {
+				w = b.Uint64()
+			}
+			if w%n != v {
+				t.Fatalf("Uint64n(%v) = %v, stream gives %v", n, v, w%n)
+			}
+
+			u := Uint32n(a, m)
+			limit32 := ^uint32(0) - ^uint32(0)%m
+			x := b.Uint32()
+			for x >= limit32 {
+				x = b.Uint32()
+			}
+			if u >= m || x%m != u {
+				t.Fatalf("Uint32n(%v) = %v, stream gives %v", m, u, x%m)
+			}
+		}
+	})
+}
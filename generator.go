@@ -0,0 +1,17 @@
+package isaac
+
+// Generator is the common interface of the random generators in this package.
+type Generator interface {
+	Int63() int64
+	Uint32() uint32
+	Uint64() uint64
+	Int31() int32
+	Int() int
+}
+
+var (
+	_ Generator = (*Isaac)(nil)
+	_ Generator = (*Isaac64)(nil)
+	_ Generator = (*ReseedingIsaac)(nil)
+	_ Generator = (*ReseedingIsaac64)(nil)
+)
@@ -0,0 +1,182 @@
+package isaac
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	defaultHealthAlpha = 1.0 / (1 << 40)
+	aptWindow          = 512
+)
+
+// HealthConfig configures the continuous health tests of a generator, which
+// follow the repetition count and adaptive proportion tests of NIST SP
+// 800-90B section 4.4 with each word treated as a sample of full entropy.
+type HealthConfig struct {
+	// Alpha is the false positive probability of a single test. Zero
+	// selects 2^-40.
+	Alpha float64
+
+	// OnFailure is called with a *HealthError whenever a test fails. The
+	// generator keeps running, so OnFailure may decide to Wipe it, reseed
+	// it or forward the error to a channel.
+	OnFailure func(error)
+}
+
+// HealthError describes a failed health test.
+type HealthError struct {
+	// Test is one of "repetition-count", "adaptive-proportion" and
+	// "stuck-state".
+	Test string
+
+	// Block is the number of blocks generated since the health tests were
+	// enabled, counting the failing block.
+	Block uint64
+}
+
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("isaac: %s health test failed in block %d", e.Test, e.Block)
+}
+
+type healthMonitor struct {
+	onFailure func(error)
+	rctCutoff int
+	aptCutoff int
+	block     uint64
+
+	last uint64
+	run  int
+
+	aptFirst uint64
+	aptCount int
+	aptSeen  int
+}
+
+func newHealthMonitor(cfg HealthConfig, bits int) *healthMonitor {
+	alpha := cfg.Alpha
+	if alpha <= 0 || alpha >= 1 {
+		alpha = defaultHealthAlpha
+	}
+
+	return &healthMonitor{
+		onFailure: cfg.OnFailure,
+		rctCutoff: 1 + int(math.Ceil(-math.Log2(alpha)/float64(bits))),
+		aptCutoff: aptCutoff(alpha, bits),
+	}
+}
+
+// aptCutoff returns the smallest count c such that the first sample of a
+// window occurs at least c times with probability at most alpha.
+func aptCutoff(alpha float64, bits int) int {
+	p := math.Exp2(-float64(bits))
+	n := aptWindow - 1
+
+	// tail holds P(X >= k+1) for X ~ Binomial(n, p) and is built from the
+	// top down in log space to stay accurate for tiny p.
+	tail := 0.0
+	for k := n; k >= 0; k-- {
+		lg1, _ := math.Lgamma(float64(n + 1))
+		lg2, _ := math.Lgamma(float64(k + 1))
+		lg3, _ := math.Lgamma(float64(n - k + 1))
+		logPMF := lg1 - lg2 - lg3 + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p)
+		if tail+math.Exp(logPMF) > alpha {
+			// The first sample itself is part of the count.
+			return k + 2
+		}
+		tail += math.Exp(logPMF)
+	}
+
+	return 1
+}
+
+func (h *healthMonitor) fail(test string) {
+	if h.onFailure != nil {
+		h.onFailure(&HealthError{Test: test, Block: h.block})
+	}
+}
+
+func (h *healthMonitor) sample(x uint64) {
+	if h.run > 0 && x == h.last {
+		h.run++
+		if h.run == h.rctCutoff {
+			h.fail("repetition-count")
+		}
+	} else {
+		h.last, h.run = x, 1
+	}
+
+	if h.aptSeen == 0 {
+		h.aptFirst, h.aptCount = x, 1
+	} else if x == h.aptFirst {
+		h.aptCount++
+		if h.aptCount == h.aptCutoff {
+			h.fail("adaptive-proportion")
+		}
+	}
+	if h.aptSeen++; h.aptSeen == aptWindow {
+		h.aptSeen = 0
+	}
+}
+
+// checkState32 runs the stuck state test on mm before a block is generated.
+func (h *healthMonitor) checkState32(mm []uint32) {
+	h.block++
+
+	var acc uint32
+	for _, v := range mm {
+		acc |= v
+	}
+	if acc == 0 {
+		h.fail("stuck-state")
+	}
+}
+
+// checkBlock32 runs the sample tests on a generated block.
+func (h *healthMonitor) checkBlock32(r []uint32) {
+	for _, v := range r {
+		h.sample(uint64(v))
+	}
+}
+
+// checkState64 runs the stuck state test on mm before a block is generated.
+func (h *healthMonitor) checkState64(mm []uint64) {
+	h.block++
+
+	var acc uint64
+	for _, v := range mm {
+		acc |= v
+	}
+	if acc == 0 {
+		h.fail("stuck-state")
+	}
+}
+
+// checkBlock64 runs the sample tests on a generated block.
+func (h *healthMonitor) checkBlock64(r []uint64) {
+	for _, v := range r {
+		h.sample(v)
+	}
+}
+
+// EnableHealthTests enables continuous health tests on every block generated
+// by ISAAC instance from now on. They are disabled by default.
+func (ctx *Isaac) EnableHealthTests(cfg HealthConfig) {
+	ctx.health = newHealthMonitor(cfg, 32)
+}
+
+// DisableHealthTests disables continuous health tests.
+func (ctx *Isaac) DisableHealthTests() {
+	ctx.health = nil
+}
+
+// EnableHealthTests enables continuous health tests on every block generated
+// by ISAAC64 instance from now on. They are disabled by default.
+func (ctx *Isaac64) EnableHealthTests(cfg HealthConfig) {
+	ctx.health = newHealthMonitor(cfg, 64)
+}
+
+// DisableHealthTests disables continuous health tests.
+func (ctx *Isaac64) DisableHealthTests() {
+	ctx.health = nil
+}
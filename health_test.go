@@ -0,0 +1,91 @@
+package isaac
+
+import "testing"
+
+func TestHealthCutoffs(t *testing.T) {
+	h := newHealthMonitor(HealthConfig{}, 32)
+	if h.rctCutoff != 3 || h.aptCutoff != 3 {
+		t.Fatalf("unexpected 32-bit cutoffs %v, %v", h.rctCutoff, h.aptCutoff)
+	}
+
+	h = newHealthMonitor(HealthConfig{Alpha: 1.0 / (1 << 20)}, 8)
+	if h.rctCutoff != 4 || h.aptCutoff != 14 {
+		t.Fatalf("unexpected 8-bit cutoffs %v, %v", h.rctCutoff, h.aptCutoff)
+	}
+}
+
+func TestHealthSampleTests(t *testing.T) {
+	var failures []string
+	h := newHealthMonitor(HealthConfig{
+		OnFailure: func(err error) { failures = append(failures, err.(*HealthError).Test) },
+	}, 32)
+
+	h.sample(1)
+	h.sample(2)
+	h.sample(2)
+	if len(failures) != 0 {
+		t.Fatalf("unexpected failures %v", failures)
+	}
+	h.sample(2)
+	if len(failures) != 1 || failures[0] != "repetition-count" {
+		t.Fatalf("expected repetition-count failure, got %v", failures)
+	}
+
+	// Start a fresh window whose first sample recurs without repeating.
+	h = newHealthMonitor(HealthConfig{OnFailure: h.onFailure}, 32)
+	failures = nil
+	for i := uint64(0); i < aptWindow; i++ {
+		if i%100 == 0 {
+			h.sample(7)
+		} else {
+			h.sample(i)
+		}
+	}
+	if len(failures) != 1 || failures[0] != "adaptive-proportion" {
+		t.Fatalf("expected adaptive-proportion failure, got %v", failures)
+	}
+}
+
+func TestHealthStuckState(t *testing.T) {
+	var errs []error
+	isa := NewIsaac()
+	isa.EnableHealthTests(HealthConfig{OnFailure: func(err error) { errs = append(errs, err) }})
+
+	// Using an unseeded instance generates a block from all-zero memory,
+	// which also fails the sample tests.
+	isa.Uint32()
+	if len(errs) == 0 || errs[0].(*HealthError).Test != "stuck-state" || errs[0].(*HealthError).Block != 1 {
+		t.Fatalf("expected stuck-state failure, got %v", errs)
+	}
+
+	errs = nil
+	isa64 := NewIsaac64()
+	isa64.EnableHealthTests(HealthConfig{OnFailure: func(err error) { errs = append(errs, err) }})
+	isa64.Uint64()
+	if len(errs) == 0 || errs[0].Error() != "isaac: stuck-state health test failed in block 1" {
+		t.Fatalf("expected stuck-state failure, got %v", errs)
+	}
+}
+
+func TestHealthSeeded(t *testing.T) {
+	fail := func(err error) { t.Fatal(err) }
+
+	isa := NewIsaac()
+	isa.EnableHealthTests(HealthConfig{OnFailure: fail})
+	isa.Seed(1)
+	for i := 0; i < 1<<16; i++ {
+		isa.Uint32()
+	}
+
+	isa64 := NewIsaac64()
+	isa64.EnableHealthTests(HealthConfig{OnFailure: fail})
+	isa64.Seed(1)
+	for i := 0; i < 1<<16; i++ {
+		isa64.Uint64()
+	}
+
+	isa64.DisableHealthTests()
+	if isa64.health != nil {
+		t.Fatal("health tests still enabled")
+	}
+}
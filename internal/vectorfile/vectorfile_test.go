@@ -0,0 +1,53 @@
+package vectorfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, alg := range []string{"isaac", "isaac64"} {
+		vf := &File{Alg: alg, Seed: []byte("seed")}
+		for i := 0; i < 16; i++ {
+			vf.Words = append(vf.Words, uint64(i)*0x01010101)
+		}
+
+		var buf bytes.Buffer
+		if err := vf.Write(&buf, true); err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(buf.String(), "\n"); n != 2+16/WordsPerLine(alg) {
+			t.Fatalf("%s: %v lines\n%s", alg, n, buf.String())
+		}
+
+		got, err := Parse(&buf, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Alg != alg || string(got.Seed) != "seed" || len(got.Words) != len(vf.Words) {
+			t.Fatalf("%s: read back %+v", alg, got)
+		}
+		for i := range vf.Words {
+			if got.Words[i] != vf.Words[i] {
+				t.Fatalf("%s [%v] %x != %x", alg, i, got.Words[i], vf.Words[i])
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct{ alg, text string }{
+		{"", "00000000\n"},
+		{"isaac", "# alg: isaac128\n"},
+		{"isaac", "# seed: xyz\n"},
+		{"isaac", "0000000\n"},
+		{"isaac64", "000000000000000g\n"},
+	}
+
+	for _, c := range cases {
+		if _, err := Parse(strings.NewReader(c.text), c.alg); err == nil {
+			t.Errorf("%q: expected error", c.text)
+		}
+	}
+}
@@ -6,50 +6,128 @@
 
 package isaac
 
-import "unsafe"
+// goldenRatio32 is the default initial value of a..h in randInit.
+var goldenRatio32 = [8]uint32{0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9}
 
 // Isaac represents ISAAC random generator
+//
+// A copy of an Isaac value is an independent snapshot of its state, except
+// for instances of other sizes made by NewIsaacSize: their state lives in a
+// buffer that copies share, so use Clone to copy them.
 type Isaac struct {
-	randrsl [256]uint32
-	randmem [256]uint32
-	randcnt uint32
-	aa      uint32
-	bb      uint32
-	cc      uint32
+	randrsl  [256]uint32
+	randmem  [256]uint32
+	sized    *sized32
+	randcnt  uint32
+	absorbed uint32
+	resv     bitReservoir
+	aa       uint32
+	bb       uint32
+	cc       uint32
+	wiped    bool
+	health   *healthMonitor
+	init     *[8]uint32
+	compat   bool
 }
 
 // NewIsaac returns a new instance of ISAAC.
 func NewIsaac() *Isaac {
-	return &Isaac{
-		randmem: [256]uint32{},
-		randrsl: [256]uint32{},
-		randcnt: 0,
-		aa:      0,
-		bb:      0,
-		cc:      0,
-	}
+	return NewIsaacSize(defaultSizeLog2)
+}
+
+// NewIsaacSize returns a new instance of ISAAC whose state is 2^log2Words
+// words, as RANDSIZL of the reference implementation selects. NewIsaac uses
+// 2^8 words. It panics if log2Words is not between MinSizeLog2 and
+// MaxSizeLog2.
+func NewIsaacSize(log2Words int) *Isaac {
+	checkSizeLog2(log2Words)
+	ctx := &Isaac{}
+	ctx.alloc(uint(log2Words))
+	return ctx
+}
+
+// NewIsaacUnseeded returns a new instance of ISAAC initialized without a
+// seed, as randinit(ctx, FALSE) of the reference implementation does.
+func NewIsaacUnseeded() *Isaac {
+	ctx := NewIsaac()
+	ctx.InitUnseeded()
+	return ctx
+}
+
+// InitUnseeded initializes the state of ISAAC instance without a seed, as
+// randinit(ctx, FALSE) of the reference implementation does.
+func (ctx *Isaac) InitUnseeded() {
+	ctx.resetSeed()
+	ctx.randInit(false)
+}
+
+// SetInitValues replaces the golden ratio used as the initial value of the
+// eight mixing variables a..h in initialization. It takes effect on the
+// next initialization of the state.
+func (ctx *Isaac) SetInitValues(v [8]uint32) {
+	ctx.init = &v
 }
 
 // Seed initializes the state of ISAAC instance using given 64bit integer.
 func (ctx *Isaac) Seed(seed int64) {
-	ctx.randrsl[0] = uint32(seed)
-	ctx.randrsl[1] = uint32(seed >> 32)
+	ctx.resetSeed()
+	r := ctx.rsl()
+	r[0] = uint32(seed)
+	r[1] = uint32(seed >> 32)
 	ctx.randInit(true)
 }
 
 // SeedBytes initializes the state of ISAAC instance using given byte sequence.
 func (ctx *Isaac) SeedBytes(seed []byte) {
-	if len(seed) > 1024 {
-		seed = seed[:1024]
-	}
-
-	unsafeCopy(unsafe.Pointer(&ctx.randrsl[0]), unsafe.Pointer(&seed[0]), len(seed))
+	ctx.resetSeed()
+	copySeed32(ctx.rsl(), seed)
 	ctx.randInit(true)
 }
 
 // SeedString initializes the state of ISAAC instance using given string.
 func (ctx *Isaac) SeedString(seed string) {
-	ctx.SeedBytes([]byte(seed))
+	b := []byte(seed)
+	ctx.SeedBytes(b)
+	wipeBytes(b)
+}
+
+// Clone returns an independent copy of ISAAC instance, which continues with the
+// same stream as ctx from its current position. Settings such as
+// SetInitValues and health tests are copied along.
+func (ctx *Isaac) Clone() *Isaac {
+	c := *ctx
+	if ctx.sized != nil {
+		c.sized = &sized32{
+			rsl:  append([]uint32(nil), ctx.sized.rsl...),
+			mem:  append([]uint32(nil), ctx.sized.mem...),
+			sizl: ctx.sized.sizl,
+		}
+	}
+	if ctx.health != nil {
+		h := *ctx.health
+		c.health = &h
+	}
+
+	return &c
+}
+
+// Wipe overwrites the whole state of ISAAC instance with zeros. The
+// instance is unusable afterwards and any further use of it panics.
+func (ctx *Isaac) Wipe() {
+	r, m := ctx.rsl(), ctx.mem()
+	for i := range r {
+		r[i] = 0
+		m[i] = 0
+	}
+	ctx.randcnt, ctx.absorbed = 0, 0
+	ctx.resv = bitReservoir{}
+	ctx.aa, ctx.bb, ctx.cc = 0, 0, 0
+	ctx.init = nil
+	if ctx.health != nil {
+		*ctx.health = healthMonitor{}
+		ctx.health = nil
+	}
+	ctx.wiped = true
 }
 
 // Int63 returns a non-negative 63-bit integer as an int64.
@@ -59,12 +137,43 @@ func (ctx *Isaac) Int63() int64 {
 
 // Uint32 returns a random 32-bit unsigned integer.
 func (ctx *Isaac) Uint32() uint32 {
-	return ctx.next()
+	// This is next written out, as a call to it would be too costly to
+	// inline.
+	if ctx.randcnt == 0 || ctx.sized != nil {
+		return ctx.nextSlow()
+	}
+
+	ctx.randcnt--
+	return ctx.randrsl[ctx.randcnt]
 }
 
 // Uint64 returns a random 64-bit unsigned integer.
+//
+// The value is made of two adjacent words of the current block, consumed in
+// the same downward order as Uint32: if Uint32 would return randrsl[i] next,
+// Uint64 returns randrsl[i] as the high and randrsl[i-1] as the low half. A
+// single word left at the bottom of a block is skipped, so a value never
+// straddles two blocks. See SetUint64Compat for the order of older versions.
 func (ctx *Isaac) Uint64() uint64 {
-	return (uint64(ctx.next()) << 32) | uint64(ctx.next())
+	if ctx.compat {
+		return (uint64(ctx.next()) << 32) | uint64(ctx.next())
+	}
+
+	if ctx.randcnt < 2 {
+		ctx.refill()
+	}
+
+	ctx.randcnt -= 2
+	r, i := ctx.rsl(), ctx.randcnt
+	return uint64(r[i+1])<<32 | uint64(r[i])
+}
+
+// SetUint64Compat restores the older behavior of Uint64 and the functions
+// based on it, for streams generated by earlier versions. In compat mode a
+// value is built from two calls of Uint32, high half first, and straddles
+// two blocks when only one word is left.
+func (ctx *Isaac) SetUint64Compat(compat bool) {
+	ctx.compat = compat
 }
 
 // Int31 returns a non-negative 31-bit integer as an int32.
@@ -79,12 +188,31 @@ func (ctx *Isaac) Int() int {
 }
 
 func (ctx *Isaac) isaac() {
-	var a, b, x uint32
-	mm := ctx.randmem[:]
-	r := ctx.randrsl[:]
+	r, m := ctx.rsl(), ctx.mem()
+	if ctx.health != nil {
+		ctx.health.checkState32(m)
+	}
+
 	ctx.cc++
-	a, b = ctx.aa, ctx.bb+ctx.cc
+	if ctx.sized == nil {
+		ctx.aa, ctx.bb = isaacFixed(m, r, ctx.aa, ctx.bb+ctx.cc)
+	} else {
+		ctx.aa, ctx.bb = isaacSized(m, r, ctx.sized.sizl, ctx.aa, ctx.bb+ctx.cc)
+	}
+
+	if ctx.health != nil {
+		ctx.health.checkBlock32(r)
+	}
+}
+
+// isaacFixed generates a block of 2^8 words with the masks of the reference
+// implementation folded into constants. It is about 25% faster than
+// isaacSized at this size, so the default size keeps its own loop; TestSize
+// checks isaacSized against the reference at the other sizes.
+func isaacFixed(mm, r []uint32, a, b uint32) (uint32, uint32) {
+	mm, r = mm[:256], r[:256]
 
+	var x uint32
 	for ii := 0; ii < 256; ii += 4 {
 		var i uint8 = uint8(ii)
 
@@ -113,12 +241,62 @@ func (ctx *Isaac) isaac() {
 		b = r[i+3]
 	}
 
-	ctx.bb, ctx.aa = b, a
+	return a, b
+}
+
+// isaacSized generates a block of 2^sizl words.
+func isaacSized(mm, r []uint32, sizl uint, a, b uint32) (uint32, uint32) {
+	n := len(mm)
+	r = r[:n]
+	mask, half := n-1, n>>1
+	shift := sizl + 2
+
+	var x uint32
+	for i := 0; i < n; i += 4 {
+		m, o := mm[i:i+4], r[i:i+4]
+		x = m[0]
+		a = (a ^ (a << 13)) + mm[(i+half)&mask]
+		m[0] = mm[int(x>>2)&mask] + a + b
+		o[0] = mm[int(m[0]>>shift)&mask] + x
+		b = o[0]
+
+		x = m[1]
+		a = (a ^ (a >> 6)) + mm[(i+half+1)&mask]
+		m[1] = mm[int(x>>2)&mask] + a + b
+		o[1] = mm[int(m[1]>>shift)&mask] + x
+		b = o[1]
+
+		x = m[2]
+		a = (a ^ (a << 2)) + mm[(i+half+2)&mask]
+		m[2] = mm[int(x>>2)&mask] + a + b
+		o[2] = mm[int(m[2]>>shift)&mask] + x
+		b = o[2]
+
+		x = m[3]
+		a = (a ^ (a >> 16)) + mm[(i+half+3)&mask]
+		m[3] = mm[int(x>>2)&mask] + a + b
+		o[3] = mm[int(m[3]>>shift)&mask] + x
+		b = o[3]
+	}
+
+	return a, b
 }
 
 func (ctx *Isaac) randInit(flag bool) {
-	var a, b, c, d, e, f, g, h uint32
-	a, b, c, d, e, f, g, h = 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9, 0x9e3779b9
+	if ctx.wiped {
+		panic(errWiped)
+	}
+	ctx.aa, ctx.bb, ctx.cc = 0, 0, 0
+	ctx.absorbed = 0
+	ctx.resv = bitReservoir{}
+
+	iv := goldenRatio32
+	if ctx.init != nil {
+		iv = *ctx.init
+	}
+
+	r, m := ctx.rsl(), ctx.mem()
+	a, b, c, d, e, f, g, h := iv[0], iv[1], iv[2], iv[3], iv[4], iv[5], iv[6], iv[7]
 
 	// scramble
 	for i := 0; i < 4; i++ {
@@ -150,15 +328,15 @@ func (ctx *Isaac) randInit(flag bool) {
 
 	if flag {
 		// initialize using seed
-		for i := 0; i < 256; i += 8 {
-			a += ctx.randrsl[i]
-			b += ctx.randrsl[i+1]
-			c += ctx.randrsl[i+2]
-			d += ctx.randrsl[i+3]
-			e += ctx.randrsl[i+4]
-			f += ctx.randrsl[i+5]
-			g += ctx.randrsl[i+6]
-			h += ctx.randrsl[i+7]
+		for i := 0; i < len(r); i += 8 {
+			a += r[i]
+			b += r[i+1]
+			c += r[i+2]
+			d += r[i+3]
+			e += r[i+4]
+			f += r[i+5]
+			g += r[i+6]
+			h += r[i+7]
 
 			// mix
 			a ^= b << 11
@@ -186,26 +364,26 @@ func (ctx *Isaac) randInit(flag bool) {
 			c += h
 			a += b
 
-			ctx.randmem[i] = a
-			ctx.randmem[i+1] = b
-			ctx.randmem[i+2] = c
-			ctx.randmem[i+3] = d
-			ctx.randmem[i+4] = e
-			ctx.randmem[i+5] = f
-			ctx.randmem[i+6] = g
-			ctx.randmem[i+7] = h
+			m[i] = a
+			m[i+1] = b
+			m[i+2] = c
+			m[i+3] = d
+			m[i+4] = e
+			m[i+5] = f
+			m[i+6] = g
+			m[i+7] = h
 		}
 
 		// second pass
-		for i := 0; i < 256; i += 8 {
-			a += ctx.randmem[i]
-			b += ctx.randmem[i+1]
-			c += ctx.randmem[i+2]
-			d += ctx.randmem[i+3]
-			e += ctx.randmem[i+4]
-			f += ctx.randmem[i+5]
-			g += ctx.randmem[i+6]
-			h += ctx.randmem[i+7]
+		for i := 0; i < len(r); i += 8 {
+			a += m[i]
+			b += m[i+1]
+			c += m[i+2]
+			d += m[i+3]
+			e += m[i+4]
+			f += m[i+5]
+			g += m[i+6]
+			h += m[i+7]
 
 			// mix
 			a ^= b << 11
@@ -233,17 +411,17 @@ func (ctx *Isaac) randInit(flag bool) {
 			c += h
 			a += b
 
-			ctx.randmem[i] = a
-			ctx.randmem[i+1] = b
-			ctx.randmem[i+2] = c
-			ctx.randmem[i+3] = d
-			ctx.randmem[i+4] = e
-			ctx.randmem[i+5] = f
-			ctx.randmem[i+6] = g
-			ctx.randmem[i+7] = h
+			m[i] = a
+			m[i+1] = b
+			m[i+2] = c
+			m[i+3] = d
+			m[i+4] = e
+			m[i+5] = f
+			m[i+6] = g
+			m[i+7] = h
 		}
 	} else {
-		for i := 0; i < 256; i += 8 {
+		for i := 0; i < len(r); i += 8 {
 			// mix
 			a ^= b << 11
 			d += a
@@ -270,28 +448,103 @@ func (ctx *Isaac) randInit(flag bool) {
 			c += h
 			a += b
 
-			ctx.randmem[i] = a
-			ctx.randmem[i+1] = b
-			ctx.randmem[i+2] = c
-			ctx.randmem[i+3] = d
-			ctx.randmem[i+4] = e
-			ctx.randmem[i+5] = f
-			ctx.randmem[i+6] = g
-			ctx.randmem[i+7] = h
+			m[i] = a
+			m[i+1] = b
+			m[i+2] = c
+			m[i+3] = d
+			m[i+4] = e
+			m[i+5] = f
+			m[i+6] = g
+			m[i+7] = h
 		}
 	}
 
 	ctx.isaac()
-	ctx.randcnt = 256
+	ctx.randcnt = uint32(len(r))
+}
+
+func (ctx *Isaac) refill() {
+	if ctx.wiped {
+		panic(errWiped)
+	}
+
+	ctx.isaac()
+	ctx.randcnt = uint32(len(ctx.rsl()))
 }
 
 func (ctx *Isaac) next() uint32 {
-	if ctx.randcnt == 0 {
-		ctx.isaac()
-		ctx.randcnt = 255
-		return ctx.randrsl[255]
+	if ctx.randcnt == 0 || ctx.sized != nil {
+		return ctx.nextSlow()
 	}
 
 	ctx.randcnt--
 	return ctx.randrsl[ctx.randcnt]
 }
+
+// nextSlow is next for an empty block or a state of another size, kept out
+// of next so that next can be inlined.
+func (ctx *Isaac) nextSlow() uint32 {
+	if ctx.randcnt == 0 {
+		ctx.refill()
+	}
+
+	ctx.randcnt--
+	return ctx.rsl()[ctx.randcnt]
+}
+
+// sized32 holds the state of an instance whose size is not the default
+// 2^8 words.
+type sized32 struct {
+	rsl  []uint32
+	mem  []uint32
+	sizl uint
+}
+
+// alloc gives ctx a zero state of 2^sizl words.
+func (ctx *Isaac) alloc(sizl uint) {
+	ctx.randrsl, ctx.randmem = [256]uint32{}, [256]uint32{}
+	ctx.sized = nil
+	if sizl != defaultSizeLog2 {
+		ctx.sized = &sized32{
+			rsl:  make([]uint32, 1<<sizl),
+			mem:  make([]uint32, 1<<sizl),
+			sizl: sizl,
+		}
+	}
+}
+
+// rsl returns the results of the current block.
+func (ctx *Isaac) rsl() []uint32 {
+	if ctx.sized != nil {
+		return ctx.sized.rsl
+	}
+	return ctx.randrsl[:]
+}
+
+// mem returns the internal memory of the state.
+func (ctx *Isaac) mem() []uint32 {
+	if ctx.sized != nil {
+		return ctx.sized.mem
+	}
+	return ctx.randmem[:]
+}
+
+// sizeLog2 returns the log2 of the size of the state in words.
+func (ctx *Isaac) sizeLog2() uint {
+	if ctx.sized != nil {
+		return ctx.sized.sizl
+	}
+	return defaultSizeLog2
+}
+
+// resetSeed clears the results before a seed is written to them.
+func (ctx *Isaac) resetSeed() {
+	if ctx.wiped {
+		panic(errWiped)
+	}
+
+	r := ctx.rsl()
+	for i := range r {
+		r[i] = 0
+	}
+}
@@ -6,49 +6,129 @@
 
 package isaac
 
-import "unsafe"
+// goldenRatio64 is the default initial value of a..h in randInit.
+var goldenRatio64 = [8]uint64{0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13}
 
 // Isaac64 represents ISAAC64 random generator
+//
+// A copy of an Isaac64 value is an independent snapshot of its state, except
+// for instances of other sizes made by NewIsaac64Size: their state lives in a
+// buffer that copies share, so use Clone to copy them.
 type Isaac64 struct {
-	randrsl [256]uint64
-	randmem [256]uint64
-	randcnt uint64
-	aa      uint64
-	bb      uint64
-	cc      uint64
+	randrsl  [256]uint64
+	randmem  [256]uint64
+	sized    *sized64
+	randcnt  uint64
+	absorbed uint64
+	resv     bitReservoir
+	aa       uint64
+	bb       uint64
+	cc       uint64
+	wiped    bool
+	health   *healthMonitor
+	init     *[8]uint64
+	halves   bool
+	half     uint32
+	hasHalf  bool
 }
 
 // NewIsaac64 returns a new instance of ISAAC64.
 func NewIsaac64() *Isaac64 {
-	return &Isaac64{
-		randmem: [256]uint64{},
-		randrsl: [256]uint64{},
-		randcnt: 0,
-		aa:      0,
-		bb:      0,
-		cc:      0,
-	}
+	return NewIsaac64Size(defaultSizeLog2)
+}
+
+// NewIsaac64Size returns a new instance of ISAAC64 whose state is 2^log2Words
+// words, as RANDSIZL of the reference implementation selects. NewIsaac64 uses
+// 2^8 words. It panics if log2Words is not between MinSizeLog2 and
+// MaxSizeLog2.
+func NewIsaac64Size(log2Words int) *Isaac64 {
+	checkSizeLog2(log2Words)
+	ctx := &Isaac64{}
+	ctx.alloc(uint(log2Words))
+	return ctx
+}
+
+// NewIsaac64Unseeded returns a new instance of ISAAC64 initialized without a
+// seed, as randinit(FALSE) of the reference implementation does.
+func NewIsaac64Unseeded() *Isaac64 {
+	ctx := NewIsaac64()
+	ctx.InitUnseeded()
+	return ctx
+}
+
+// InitUnseeded initializes the state of ISAAC64 instance without a seed, as
+// randinit(ctx, FALSE) of the reference implementation does.
+func (ctx *Isaac64) InitUnseeded() {
+	ctx.resetSeed()
+	ctx.randInit(false)
+}
+
+// SetInitValues replaces the golden ratio used as the initial value of the
+// eight mixing variables a..h in initialization. It takes effect on the
+// next initialization of the state.
+func (ctx *Isaac64) SetInitValues(v [8]uint64) {
+	ctx.init = &v
 }
 
 // Seed initializes the state of ISAAC instance using given 64bit integer.
 func (ctx *Isaac64) Seed(seed int64) {
-	ctx.randrsl[0] = uint64(seed)
+	ctx.resetSeed()
+	ctx.rsl()[0] = uint64(seed)
 	ctx.randInit(true)
 }
 
 // SeedBytes initializes the state of ISAAC instance using given byte sequence.
 func (ctx *Isaac64) SeedBytes(seed []byte) {
-	if len(seed) > 2048 {
-		seed = seed[:2048]
-	}
-
-	unsafeCopy(unsafe.Pointer(&ctx.randrsl[0]), unsafe.Pointer(&seed[0]), len(seed))
+	ctx.resetSeed()
+	copySeed64(ctx.rsl(), seed)
 	ctx.randInit(true)
 }
 
 // SeedString initializes the state of ISAAC instance using given string.
 func (ctx *Isaac64) SeedString(seed string) {
-	ctx.SeedBytes([]byte(seed))
+	b := []byte(seed)
+	ctx.SeedBytes(b)
+	wipeBytes(b)
+}
+
+// Clone returns an independent copy of ISAAC64 instance, which continues with the
+// same stream as ctx from its current position. Settings such as
+// SetInitValues and health tests are copied along.
+func (ctx *Isaac64) Clone() *Isaac64 {
+	c := *ctx
+	if ctx.sized != nil {
+		c.sized = &sized64{
+			rsl:  append([]uint64(nil), ctx.sized.rsl...),
+			mem:  append([]uint64(nil), ctx.sized.mem...),
+			sizl: ctx.sized.sizl,
+		}
+	}
+	if ctx.health != nil {
+		h := *ctx.health
+		c.health = &h
+	}
+
+	return &c
+}
+
+// Wipe overwrites the whole state of ISAAC64 instance with zeros. The
+// instance is unusable afterwards and any further use of it panics.
+func (ctx *Isaac64) Wipe() {
+	r, m := ctx.rsl(), ctx.mem()
+	for i := range r {
+		r[i] = 0
+		m[i] = 0
+	}
+	ctx.randcnt, ctx.absorbed = 0, 0
+	ctx.resv = bitReservoir{}
+	ctx.aa, ctx.bb, ctx.cc = 0, 0, 0
+	ctx.init = nil
+	ctx.half, ctx.hasHalf = 0, false
+	if ctx.health != nil {
+		*ctx.health = healthMonitor{}
+		ctx.health = nil
+	}
+	ctx.wiped = true
 }
 
 // Int63 returns a non-negative 63-bit integer as an int64.
@@ -56,9 +136,21 @@ func (ctx *Isaac64) Int63() int64 {
 	return int64(ctx.Uint64() & uintMask)
 }
 
-// Uint32 returns a random 32-bit unsigned integer.
+// Uint32 returns a random 32-bit unsigned integer. By default it takes the
+// low half of a 64-bit word and discards the rest. See SetHalfWords.
 func (ctx *Isaac64) Uint32() uint32 {
-	return uint32(ctx.next())
+	if !ctx.halves {
+		return uint32(ctx.next())
+	}
+
+	if ctx.hasHalf {
+		ctx.hasHalf = false
+		return ctx.half
+	}
+
+	w := ctx.next()
+	ctx.half, ctx.hasHalf = uint32(w>>32), true
+	return uint32(w)
 }
 
 // Uint64 returns a random 64-bit unsigned integer.
@@ -68,9 +160,23 @@ func (ctx *Isaac64) Uint64() uint64 {
 
 // Int31 returns a non-negative 31-bit integer as an int32.
 func (ctx *Isaac64) Int31() int32 {
+	if ctx.halves {
+		return int32(ctx.Uint32() >> 1)
+	}
+
 	return int32(ctx.Int63() >> 32)
 }
 
+// SetHalfWords makes Uint32 and Int31 use both halves of a 64-bit word, low
+// half first, instead of one word per call. The remaining half is kept for
+// the next 32-bit call and is not consumed by Uint64, Int63 or Int. It is
+// dropped when the instance is seeded again. The mode is off by default so
+// that streams generated by earlier versions stay reproducible.
+func (ctx *Isaac64) SetHalfWords(halves bool) {
+	ctx.halves = halves
+	ctx.half, ctx.hasHalf = 0, false
+}
+
 // Int returns a non-negative integer as an int
 func (ctx *Isaac64) Int() int {
 	u := uint(ctx.Uint64())
@@ -78,12 +184,29 @@ func (ctx *Isaac64) Int() int {
 }
 
 func (ctx *Isaac64) isaac64() {
-	var a, b, x uint64
-	mm := ctx.randmem[:]
-	r := ctx.randrsl[:]
+	r, m := ctx.rsl(), ctx.mem()
+	if ctx.health != nil {
+		ctx.health.checkState64(m)
+	}
+
 	ctx.cc++
-	a, b = ctx.aa, ctx.bb+ctx.cc
+	if ctx.sized == nil {
+		ctx.aa, ctx.bb = isaac64Fixed(m, r, ctx.aa, ctx.bb+ctx.cc)
+	} else {
+		ctx.aa, ctx.bb = isaac64Sized(m, r, ctx.sized.sizl, ctx.aa, ctx.bb+ctx.cc)
+	}
 
+	if ctx.health != nil {
+		ctx.health.checkBlock64(r)
+	}
+}
+
+// isaac64Fixed generates a block of 2^8 words with the masks of the reference
+// implementation folded into constants, like isaacFixed.
+func isaac64Fixed(mm, r []uint64, a, b uint64) (uint64, uint64) {
+	mm, r = mm[:256], r[:256]
+
+	var x uint64
 	for ii := 0; ii < 256; ii += 4 {
 		var i uint8 = uint8(ii)
 
@@ -112,12 +235,63 @@ func (ctx *Isaac64) isaac64() {
 		b = r[i+3]
 	}
 
-	ctx.bb, ctx.aa = b, a
+	return a, b
+}
+
+// isaac64Sized generates a block of 2^sizl words.
+func isaac64Sized(mm, r []uint64, sizl uint, a, b uint64) (uint64, uint64) {
+	n := len(mm)
+	r = r[:n]
+	mask, half := n-1, n>>1
+	shift := sizl + 3
+
+	var x uint64
+	for i := 0; i < n; i += 4 {
+		m, o := mm[i:i+4], r[i:i+4]
+		x = m[0]
+		a = ^(a ^ (a << 21)) + mm[(i+half)&mask]
+		m[0] = mm[int(x>>3)&mask] + a + b
+		o[0] = mm[int(m[0]>>shift)&mask] + x
+		b = o[0]
+
+		x = m[1]
+		a = (a ^ (a >> 5)) + mm[(i+half+1)&mask]
+		m[1] = mm[int(x>>3)&mask] + a + b
+		o[1] = mm[int(m[1]>>shift)&mask] + x
+		b = o[1]
+
+		x = m[2]
+		a = (a ^ (a << 12)) + mm[(i+half+2)&mask]
+		m[2] = mm[int(x>>3)&mask] + a + b
+		o[2] = mm[int(m[2]>>shift)&mask] + x
+		b = o[2]
+
+		x = m[3]
+		a = (a ^ (a >> 33)) + mm[(i+half+3)&mask]
+		m[3] = mm[int(x>>3)&mask] + a + b
+		o[3] = mm[int(m[3]>>shift)&mask] + x
+		b = o[3]
+	}
+
+	return a, b
 }
 
 func (ctx *Isaac64) randInit(flag bool) {
-	var a, b, c, d, e, f, g, h uint64
-	a, b, c, d, e, f, g, h = 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13, 0x9e3779b97f4a7c13
+	if ctx.wiped {
+		panic(errWiped)
+	}
+	ctx.aa, ctx.bb, ctx.cc = 0, 0, 0
+	ctx.half, ctx.hasHalf = 0, false
+	ctx.absorbed = 0
+	ctx.resv = bitReservoir{}
+
+	iv := goldenRatio64
+	if ctx.init != nil {
+		iv = *ctx.init
+	}
+
+	r, m := ctx.rsl(), ctx.mem()
+	a, b, c, d, e, f, g, h := iv[0], iv[1], iv[2], iv[3], iv[4], iv[5], iv[6], iv[7]
 
 	// scramble
 	for i := 0; i < 4; i++ {
@@ -149,15 +323,15 @@ func (ctx *Isaac64) randInit(flag bool) {
 
 	if flag {
 		// initialize using seed
-		for i := 0; i < 256; i += 8 {
-			a += ctx.randrsl[i]
-			b += ctx.randrsl[i+1]
-			c += ctx.randrsl[i+2]
-			d += ctx.randrsl[i+3]
-			e += ctx.randrsl[i+4]
-			f += ctx.randrsl[i+5]
-			g += ctx.randrsl[i+6]
-			h += ctx.randrsl[i+7]
+		for i := 0; i < len(r); i += 8 {
+			a += r[i]
+			b += r[i+1]
+			c += r[i+2]
+			d += r[i+3]
+			e += r[i+4]
+			f += r[i+5]
+			g += r[i+6]
+			h += r[i+7]
 
 			// mix
 			a -= e
@@ -185,26 +359,26 @@ func (ctx *Isaac64) randInit(flag bool) {
 			e ^= g << 14
 			g += h
 
-			ctx.randmem[i] = a
-			ctx.randmem[i+1] = b
-			ctx.randmem[i+2] = c
-			ctx.randmem[i+3] = d
-			ctx.randmem[i+4] = e
-			ctx.randmem[i+5] = f
-			ctx.randmem[i+6] = g
-			ctx.randmem[i+7] = h
+			m[i] = a
+			m[i+1] = b
+			m[i+2] = c
+			m[i+3] = d
+			m[i+4] = e
+			m[i+5] = f
+			m[i+6] = g
+			m[i+7] = h
 		}
 
 		// second pass
-		for i := 0; i < 256; i += 8 {
-			a += ctx.randmem[i]
-			b += ctx.randmem[i+1]
-			c += ctx.randmem[i+2]
-			d += ctx.randmem[i+3]
-			e += ctx.randmem[i+4]
-			f += ctx.randmem[i+5]
-			g += ctx.randmem[i+6]
-			h += ctx.randmem[i+7]
+		for i := 0; i < len(r); i += 8 {
+			a += m[i]
+			b += m[i+1]
+			c += m[i+2]
+			d += m[i+3]
+			e += m[i+4]
+			f += m[i+5]
+			g += m[i+6]
+			h += m[i+7]
 
 			// mix
 			a -= e
@@ -232,17 +406,17 @@ func (ctx *Isaac64) randInit(flag bool) {
 			e ^= g << 14
 			g += h
 
-			ctx.randmem[i] = a
-			ctx.randmem[i+1] = b
-			ctx.randmem[i+2] = c
-			ctx.randmem[i+3] = d
-			ctx.randmem[i+4] = e
-			ctx.randmem[i+5] = f
-			ctx.randmem[i+6] = g
-			ctx.randmem[i+7] = h
+			m[i] = a
+			m[i+1] = b
+			m[i+2] = c
+			m[i+3] = d
+			m[i+4] = e
+			m[i+5] = f
+			m[i+6] = g
+			m[i+7] = h
 		}
 	} else {
-		for i := 0; i < 256; i += 8 {
+		for i := 0; i < len(r); i += 8 {
 			// mix
 			a -= e
 			f ^= h >> 9
@@ -269,28 +443,103 @@ func (ctx *Isaac64) randInit(flag bool) {
 			e ^= g << 14
 			g += h
 
-			ctx.randmem[i] = a
-			ctx.randmem[i+1] = b
-			ctx.randmem[i+2] = c
-			ctx.randmem[i+3] = d
-			ctx.randmem[i+4] = e
-			ctx.randmem[i+5] = f
-			ctx.randmem[i+6] = g
-			ctx.randmem[i+7] = h
+			m[i] = a
+			m[i+1] = b
+			m[i+2] = c
+			m[i+3] = d
+			m[i+4] = e
+			m[i+5] = f
+			m[i+6] = g
+			m[i+7] = h
 		}
 	}
 
 	ctx.isaac64()
-	ctx.randcnt = 256
+	ctx.randcnt = uint64(len(r))
+}
+
+func (ctx *Isaac64) refill() {
+	if ctx.wiped {
+		panic(errWiped)
+	}
+
+	ctx.isaac64()
+	ctx.randcnt = uint64(len(ctx.rsl()))
 }
 
 func (ctx *Isaac64) next() uint64 {
-	if ctx.randcnt == 0 {
-		ctx.isaac64()
-		ctx.randcnt = 255
-		return ctx.randrsl[255]
+	if ctx.randcnt == 0 || ctx.sized != nil {
+		return ctx.nextSlow()
 	}
 
 	ctx.randcnt--
 	return ctx.randrsl[ctx.randcnt]
 }
+
+// nextSlow is next for an empty block or a state of another size, kept out
+// of next so that next can be inlined.
+func (ctx *Isaac64) nextSlow() uint64 {
+	if ctx.randcnt == 0 {
+		ctx.refill()
+	}
+
+	ctx.randcnt--
+	return ctx.rsl()[ctx.randcnt]
+}
+
+// sized64 holds the state of an instance whose size is not the default
+// 2^8 words.
+type sized64 struct {
+	rsl  []uint64
+	mem  []uint64
+	sizl uint
+}
+
+// alloc gives ctx a zero state of 2^sizl words.
+func (ctx *Isaac64) alloc(sizl uint) {
+	ctx.randrsl, ctx.randmem = [256]uint64{}, [256]uint64{}
+	ctx.sized = nil
+	if sizl != defaultSizeLog2 {
+		ctx.sized = &sized64{
+			rsl:  make([]uint64, 1<<sizl),
+			mem:  make([]uint64, 1<<sizl),
+			sizl: sizl,
+		}
+	}
+}
+
+// rsl returns the results of the current block.
+func (ctx *Isaac64) rsl() []uint64 {
+	if ctx.sized != nil {
+		return ctx.sized.rsl
+	}
+	return ctx.randrsl[:]
+}
+
+// mem returns the internal memory of the state.
+func (ctx *Isaac64) mem() []uint64 {
+	if ctx.sized != nil {
+		return ctx.sized.mem
+	}
+	return ctx.randmem[:]
+}
+
+// sizeLog2 returns the log2 of the size of the state in words.
+func (ctx *Isaac64) sizeLog2() uint {
+	if ctx.sized != nil {
+		return ctx.sized.sizl
+	}
+	return defaultSizeLog2
+}
+
+// resetSeed clears the results before a seed is written to them.
+func (ctx *Isaac64) resetSeed() {
+	if ctx.wiped {
+		panic(errWiped)
+	}
+
+	r := ctx.rsl()
+	for i := range r {
+		r[i] = 0
+	}
+}
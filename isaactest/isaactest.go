@@ -0,0 +1,127 @@
+// Package isaactest provides reproducible ISAAC64 generators for randomized
+// Go tests.
+//
+// All generators of a test run derive from a single run seed, which is
+// random unless given by the -isaac.seed flag of the test binary or the
+// ISAAC_SEED environment variable, in decimal or with a 0x prefix in
+// hexadecimal. The flag takes precedence. A test that fails logs the run
+// seed, and running it again with that seed replays the same streams:
+//
+//	go test -run TestShuffle -isaac.seed=0x1d2c3b4a59687766
+//
+// The generator of a test is derived from the run seed and the full name of
+// the test, so it does not depend on which other tests run or in which
+// order, and subtests get streams independent of their parent.
+package isaactest
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"flag"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+// EnvSeed is the environment variable read for the run seed when the
+// -isaac.seed flag is not set.
+const EnvSeed = "ISAAC_SEED"
+
+var seedFlag = flag.String("isaac.seed", "", "replay randomized tests with the given `seed`")
+
+var (
+	once    sync.Once
+	runSeed uint64
+	seedErr error
+
+	mu    sync.Mutex
+	calls = make(map[string]int)
+)
+
+// seed returns the run seed, choosing it on first use.
+func seed() (uint64, error) {
+	once.Do(func() {
+		runSeed, seedErr = chooseSeed(*seedFlag, os.Getenv(EnvSeed))
+	})
+
+	return runSeed, seedErr
+}
+
+// chooseSeed parses the seed given by flagValue, or by envValue if
+// flagValue is empty, and returns a random seed if neither is set.
+func chooseSeed(flagValue, envValue string) (uint64, error) {
+	s := flagValue
+	if s == "" {
+		s = envValue
+	}
+	if s != "" {
+		return strconv.ParseUint(s, 0, 64)
+	}
+
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// New returns a generator for the test t, derived from the run seed and the
+// name of t, and logs the run seed if t fails. Calls made by the same test
+// return distinct streams, in the same order on every replay.
+func New(t testing.TB) *isaac.Isaac64 {
+	t.Helper()
+
+	s, err := seed()
+	if err != nil {
+		t.Fatalf("isaactest: invalid seed: %v", err)
+	}
+
+	name := t.Name()
+	mu.Lock()
+	n := calls[name]
+	calls[name]++
+	mu.Unlock()
+
+	if n == 0 {
+		t.Cleanup(func() {
+			mu.Lock()
+			delete(calls, name)
+			mu.Unlock()
+
+			if t.Failed() {
+				t.Logf("isaactest: replay with -isaac.seed=%#x or %s=%#x", s, EnvSeed, s)
+			}
+		})
+	}
+
+	return derive(s, name, n)
+}
+
+// Subtest runs f as a subtest of t called name, like t.Run, passing it the
+// generator New returns for the subtest.
+func Subtest(t *testing.T, name string, f func(t *testing.T, g *isaac.Isaac64)) bool {
+	t.Helper()
+
+	return t.Run(name, func(t *testing.T) {
+		f(t, New(t))
+	})
+}
+
+// derive returns the generator for the n-th call of New by the test name.
+// The run seed as 8 bytes big-endian is the key of SeedFromKey, and the info
+// is the name followed by n as 4 bytes big-endian.
+func derive(seed uint64, name string, n int) *isaac.Isaac64 {
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], seed)
+
+	info := make([]byte, len(name)+4)
+	binary.BigEndian.PutUint32(info[copy(info, name):], uint32(n))
+
+	g := isaac.NewIsaac64()
+	g.SeedFromKey(key[:], nil, &isaac.KeyOptions{Info: info})
+	return g
+}
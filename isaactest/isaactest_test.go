@@ -0,0 +1,134 @@
+package isaactest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+// fakeTB records the calls made by New.
+type fakeTB struct {
+	testing.TB
+	name     string
+	failed   bool
+	cleanups []func()
+	logs     []string
+}
+
+func (f *fakeTB) Name() string                    { return f.name }
+func (f *fakeTB) Helper()                         {}
+func (f *fakeTB) Failed() bool                    { return f.failed }
+func (f *fakeTB) Cleanup(fn func())               { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeTB) Logf(s string, a ...interface{}) { f.logs = append(f.logs, fmt.Sprintf(s, a...)) }
+
+func (f *fakeTB) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func sameStream(a, b *isaac.Isaac64) bool {
+	for i := 0; i < 512; i++ {
+		if a.Uint64() != b.Uint64() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestChooseSeed(t *testing.T) {
+	cases := []struct {
+		flag, env string
+		want      uint64
+	}{
+		{"42", "", 42},
+		{"", "0x2a", 42},
+		{"7", "0x2a", 7},
+		{"0xffffffffffffffff", "", 1<<64 - 1},
+	}
+	for _, c := range cases {
+		if s, err := chooseSeed(c.flag, c.env); err != nil || s != c.want {
+			t.Errorf("flag %q env %q: %v %v, expected %v", c.flag, c.env, s, err, c.want)
+		}
+	}
+
+	for _, bad := range []string{"x", "-1", "0x1ffffffffffffffff"} {
+		if _, err := chooseSeed(bad, ""); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+
+	a, _ := chooseSeed("", "")
+	b, _ := chooseSeed("", "")
+	if a == b {
+		t.Errorf("random seeds repeat: %#x", a)
+	}
+}
+
+func TestNewReplay(t *testing.T) {
+	s, err := seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeTB{name: "TestSomething/case"}
+	g0, g1 := New(f), New(f)
+	if !sameStream(g0, derive(s, f.name, 0)) || !sameStream(g1, derive(s, f.name, 1)) {
+		t.Fatal("New does not follow the run seed")
+	}
+	if len(f.cleanups) != 1 {
+		t.Fatalf("%v cleanups registered", len(f.cleanups))
+	}
+
+	// Once the test is over, a rerun of it gets the same streams again.
+	f.finish()
+	if len(f.logs) != 0 {
+		t.Fatalf("passing test logged %q", f.logs)
+	}
+
+	f = &fakeTB{name: "TestSomething/case", failed: true}
+	if !sameStream(New(f), derive(s, f.name, 0)) {
+		t.Fatal("rerun gets a different stream")
+	}
+	f.finish()
+	if len(f.logs) != 1 || !strings.Contains(f.logs[0], fmt.Sprintf("-isaac.seed=%#x", s)) {
+		t.Fatalf("failing test logged %q", f.logs)
+	}
+}
+
+func TestDeriveIndependent(t *testing.T) {
+	streams := []*isaac.Isaac64{
+		derive(1, "TestA", 0),
+		derive(1, "TestA", 1),
+		derive(1, "TestB", 0),
+		derive(1, "TestA/sub", 0),
+		derive(2, "TestA", 0),
+	}
+
+	seen := make(map[uint64]int)
+	for i, g := range streams {
+		v := g.Uint64()
+		if j, ok := seen[v]; ok {
+			t.Fatalf("streams %v and %v start alike", j, i)
+		}
+		seen[v] = i
+	}
+}
+
+func TestSubtest(t *testing.T) {
+	s, err := seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"one", "two"} {
+		Subtest(t, name, func(t *testing.T, g *isaac.Isaac64) {
+			if !sameStream(g, derive(s, t.Name(), 0)) {
+				t.Fatal("subtest stream is not derived from its name")
+			}
+		})
+	}
+}
@@ -5,81 +5,16 @@ import (
 )
 
 func TestIsaacVectors(t *testing.T) {
-	isa := NewIsaac()
-	isa.randInit(true)
+	for _, vf := range loadVectors(t, "isaac") {
+		isa := NewIsaac()
+		isa.SeedBytes(vf.Seed)
 
-	vectors := []uint32{
-		0xf650e4c8, 0xe448e96d, 0x98db2fb4, 0xf5fad54f, 0x433f1afb, 0xedec154a, 0xd8370487, 0x46ca4f9a,
-		0x5de3743e, 0x88381097, 0xf1d444eb, 0x823cedb6, 0x6a83e1e0, 0x4a5f6355, 0xc7442433, 0x25890e2e,
-		0x7452e319, 0x57161df6, 0x38a824f3, 0x002ed713, 0x29f55449, 0x51c08d83, 0xd78cb99e, 0xa0cc74f3,
-		0x8f651659, 0xcbc8b7c2, 0xf5f71c69, 0x12ad6419, 0xe5792e1b, 0x860536b8, 0x09b3ce98, 0xd45d6d81,
-		0xf3b26129, 0x17e38f85, 0x29cf72ce, 0x349947b0, 0xc998f9ff, 0xb5e13dae, 0x32ae2a2b, 0xf7cf814c,
-		0x8ebfa303, 0xcf22e064, 0x0b923200, 0xeca4d58a, 0xef53cec4, 0xd0f7b37d, 0x9c411a2a, 0xffdf8a80,
-		0xb40e27bc, 0xb4d2f976, 0x44b89b08, 0xf37c71d5, 0x1a70e7e9, 0x0bdb9c30, 0x60dc5207, 0xb3c3f24b,
-		0xd7386806, 0x229749b5, 0x4e232cd0, 0x91dabc65, 0xa70e1101, 0x8b87437e, 0x5781414f, 0xcdbc62e2,
-		0x8107c9ff, 0x69d2e4ae, 0x3b18e752, 0xb143b688, 0x6f4e0772, 0x95138769, 0x943c3c74, 0xafc17a97,
-		0x0fd43963, 0x6a529b0b, 0xd8c58a6a, 0xa8bcc22d, 0x2db35dfe, 0xa7a2f402, 0x6cb167db, 0x538e1f4e,
-		0x7275e277, 0x1d3b8e97, 0xecc5dc91, 0x15e3a5b9, 0x03696614, 0x30ab93ec, 0xac9fe69d, 0x7bc76811,
-		0x60eda8da, 0x28833522, 0xd5295ebc, 0x5adb60e7, 0xf7e1cdd0, 0x97166d14, 0xb67ec13a, 0x210f3925,
-		0x64af0fef, 0x0d028684, 0x3aea3dec, 0xb058bafb, 0xb8b0ccfc, 0xf2b5cc05, 0xe3a662d9, 0x814bc24c,
-		0x2364a1aa, 0x37c0ed05, 0x2b36505c, 0x451e7ec8, 0x5d2a542f, 0xe43d0fbb, 0x91c8d925, 0x60d4d5f8,
-		0x12a0594b, 0x9e8a51da, 0xcd49ebdb, 0x1b0dcdc1, 0xcd57c7f7, 0xe6344451, 0x7ded386f, 0x2f36fa86,
-		0xa6d12101, 0x33bc405d, 0xb388d96c, 0xdb6dbe96, 0xfe29661c, 0x13edc0cb, 0xcb0eee4a, 0x70cc94ae,
-		0xde11ed34, 0x0606cf9f, 0x3a6ce389, 0x23d74f4e, 0xa37f63ff, 0x917bdec2, 0xd73f72d4, 0x0e7e0e67,
-		0x3d77d9a2, 0x13add922, 0x8891b3db, 0x01a9bd70, 0x56a001e3, 0xd51f093d, 0xcc033ce3, 0x5ad0d3b0,
-		0x34105a8c, 0x6a123f57, 0xbd2e5024, 0x7364944b, 0xe89b1a3b, 0x21835c4d, 0x9f39e2d9, 0xd405ded8,
-		0x294d37e5, 0xbccaaeed, 0x35a124b5, 0x6708a2bc, 0xb00960ba, 0x2a98121a, 0x4d8fae82, 0x0bb3263f,
-		0x12595a19, 0x6a107589, 0x0809e494, 0x21c171ec, 0x884d6825, 0x14c8009b, 0xb0b84e7b, 0x03fb88f4,
-		0x28e7cb78, 0x9388b13b, 0xdd2dc1d5, 0x848f520a, 0x07c28cd1, 0x68a39358, 0x72c9137d, 0x127dd430,
-		0xc613f157, 0x8c2f0d55, 0xf7d3f39f, 0x309bfb78, 0x8406b137, 0x46c0a6f5, 0x3718d597, 0x08607f04,
-		0x76904b6d, 0x04db4e13, 0xcd7411a7, 0xb510ce0e, 0xbfc7f7cc, 0xb83f957a, 0xfdfef62d, 0xc35e4580,
-		0x3ff1e524, 0x4112d96c, 0x02c9b944, 0xd5990dfb, 0xe7e26581, 0x0d9c7e7e, 0x826dfa89, 0x66f1e0ab,
-		0x30bcc764, 0xeadebeac, 0xed35e5ee, 0x0c571a7d, 0xe4f3a26a, 0xf7f58f7b, 0xadf6bc23, 0x5d023e65,
-		0x1ed3ff4e, 0xec46b0b6, 0xd2a93b51, 0xe75b41c9, 0x7e315aeb, 0x61119a5a, 0x53245b79, 0x33f6d7b1,
-		0xcae8deba, 0x50fc8194, 0xafa92a6d, 0xc87c8006, 0x4188bfcd, 0x8bace62e, 0x78ffa568, 0x5597ec0f,
-		0xb4415f7d, 0x08294766, 0xad567643, 0x09c36f90, 0x3dde9f39, 0x4a0a283c, 0x18080c8e, 0x080c79ec,
-		0x79ae4c10, 0xcb9e1563, 0x7cdd662f, 0x62d31911, 0xa4ca0cf1, 0x5cf824cd, 0x3b708f99, 0x1e16614c,
-		0xb6b9d766, 0x5de87abb, 0x7229ea81, 0xd5b2d750, 0x56e6cd21, 0xfe1e42d5, 0x96da2655, 0xc2b9aa36,
-		0xb8f6fd4a, 0x6a158d10, 0x01913fd3, 0xaf7d1fb8, 0x0b5e435f, 0x90c10757, 0x6554abda, 0x7a68710f,
-		0x82ac484f, 0xd7e1c7be, 0x95c85eaa, 0x94a302f4, 0x4d3cfbda, 0x786b2908, 0x1010b275, 0x82d53d12,
-		0x21e2a51c, 0x3d1e9150, 0xb059261d, 0xd0638e1a, 0x31860f05, 0x81f2864d, 0xff4cfc35, 0x0451516d,
-		0xbd086f26, 0xbc5654c1, 0x65dfa427, 0xa82427f5, 0x582e3014, 0xb8d2486d, 0xc79a1749, 0x9a1d7745,
-		0x8766bb54, 0x1e04a7f7, 0x3d3dff8a, 0xd5ec6bf4, 0xdbef7d9f, 0x36ec0ea3, 0x1feb2e4f, 0x15cfcc5c,
-		0xd8c423fb, 0xd0ef3cc9, 0xeb244925, 0xba5590c8, 0xa5f48ac4, 0x33c5321c, 0x613b67b2, 0x479c3a22,
-		0xe21339cc, 0x10d210aa, 0x931dd7e2, 0xef05ee06, 0xb82f2703, 0xa385cb2c, 0x5d67133c, 0x877eb7b4,
-		0x1e3437f7, 0x5afb43ae, 0x53c078f3, 0x94d90481, 0x1d964589, 0x08063a85, 0xe1322228, 0x1956b1e5,
-		0x31860f13, 0x2e7b022f, 0x21182ca3, 0x96f703ac, 0x46819e2e, 0x0d28fe52, 0x3724d4dc, 0xa0eabe6b,
-		0xc66699fd, 0xc6112fdd, 0x19c1e69c, 0x04d3658a, 0x4b55dd99, 0x31907d62, 0xf854b522, 0x4d678f26,
-		0x22ae0582, 0xeafed133, 0xe4a51d21, 0x84bd6dd6, 0xc1a51375, 0x3f28ee63, 0xfb737b1a, 0x70a1660e,
-		0x8a8dfaa3, 0x1be79937, 0xf7476978, 0x513c1764, 0x531ac6bf, 0x12c06908, 0x001cdb95, 0x1a4b6a53,
-		0xd067fce5, 0x12b2cfb6, 0x9ddb477f, 0x740e0066, 0x39ddf25a, 0xcc8bfa2d, 0xf1b20eaf, 0x64f2632c,
-		0x9783cdee, 0x63bfd4d8, 0x0084cfe5, 0x75f4e9e2, 0x19b48fd0, 0x6c48ddd8, 0x7a36af93, 0x71865c4c,
-		0x9ce0199d, 0x867027d7, 0x2cb7b77f, 0x84ef01da, 0x72f5972f, 0x040f7074, 0xdf9afa29, 0xc921f94e,
-		0x75c08a36, 0x18c1ef9a, 0xd649a428, 0xc5b71937, 0x8a30738a, 0xd97cd348, 0x858129a6, 0x239e3b0a,
-		0xbbb8abc4, 0x80fac4c2, 0xecfcf20b, 0xd9d711f9, 0xe2a4ef71, 0xb5fe87c0, 0xbe8b06b2, 0xaafef5a7,
-		0x9c15db3b, 0x0aeb8165, 0x4389a84a, 0x253b1d7a, 0x19047c79, 0x7cdc78a2, 0xd20adf03, 0x56f55a71,
-		0x3e730fa8, 0xfd8650d8, 0x959e234e, 0xb7546681, 0xdad1b22a, 0x142a6e85, 0x8ef4bce6, 0x68235b9d,
-		0x85a13f85, 0x74096ae7, 0xa949bea2, 0x29322d0d, 0xd5683858, 0x82846526, 0x403dae08, 0x6dd1943a,
-		0xe1279bff, 0x9e7e4f04, 0x1c3a4524, 0x484525e4, 0x81d4cc5f, 0xe24124c0, 0x037464c0, 0xbf1bd691,
-		0x26ceb003, 0x275ead3a, 0xc5bde908, 0x26414ff3, 0xa30519ad, 0xd7b43abe, 0x2ce5d3d5, 0x88412761,
-		0x97ca2070, 0xe5fbb9c7, 0x276df0b4, 0x308f751f, 0x37a97df6, 0xc9cd808c, 0xfe4cb380, 0x3d469303,
-		0xaee19096, 0xc0d5d42a, 0x4e823ad3, 0xf5f9cc3b, 0x4286619c, 0x9ca45e1c, 0x66c97340, 0x891aec49,
-		0x45bae606, 0xc798f047, 0x52649d6c, 0xce86fdfc, 0x80c6e402, 0xd6ec2f2b, 0x27c82282, 0x1fe26ce0,
-		0x92f57ea7, 0xde462f4d, 0x07497cae, 0x5a48755c, 0x721502dd, 0x6cbe7935, 0x836d8003, 0x9ead7f70,
-		0x9ab3a42f, 0x4c8652d6, 0x32e39273, 0xe8fa3860, 0x1da4f25a, 0x0cd6ef81, 0x02503f7d, 0x8854a0a1,
-		0x9a30c4e8, 0x88157153, 0x05efe294, 0x57c4c925, 0x2887d96f, 0xc1a71e3c, 0xe9f84163, 0x2d0985de,
-		0xd21e796c, 0x6fb5ce56, 0x02614abf, 0xc3c7be2c, 0xb54fed6f, 0xa617a083, 0xc3142d8f, 0x6079e4ce,
-		0xceffc147, 0x1d0cb81b, 0xdc153e5f, 0xe36ef5bb, 0xd531161a, 0x165b1015, 0x7aa114ed, 0x3f7579b3,
-		0xf7f395f1, 0xbc6172c7, 0xa86f875e, 0x0e6c51b3, 0xcdfec2af, 0x73c0e762, 0x824c2009, 0xc5a87748,
-		0x94d40125, 0x8aba3ffb, 0xd32be060, 0x8c17eff0, 0x21e2547e, 0x07cffad9, 0x05340e15, 0xf3310c92,
-		0x9d8d1908, 0x86ba527f, 0xf943f672, 0xef73fbf0, 0x46d95ca5, 0xc54cd95b, 0x9d855e89, 0x4bb5af29,
-	}
-
-	for i := 0; i < 2; i++ {
-		isa.isaac()
-		for j := 0; j < 256; j++ {
-			if isa.randrsl[j] != vectors[j+i*256] {
-				t.Fatalf("[%v, %v] %x != %x", i, j, isa.randrsl[j], vectors[j+i*256])
+		for i := 0; i < len(vf.Words)/256; i++ {
+			isa.isaac()
+			for j := 0; j < 256; j++ {
+				if v := uint32(vf.Words[j+i*256]); isa.randrsl[j] != v {
+					t.Fatalf("%s [%v, %v] %x != %x", vf.name, i, j, isa.randrsl[j], v)
+				}
 			}
 		}
 	}
@@ -419,3 +354,94 @@ func TestSeedString(t *testing.T) {
 		}
 	}
 }
+
+func TestUnseeded(t *testing.T) {
+	// randinit(&ctx, FALSE) of rand.c
+	vectors := []uint32{
+		0x71d71fd2, 0xb54adae7, 0xd4788559, 0xc36129fa, 0x21dc1ea9, 0x3cb879ca, 0xd83b237f, 0xfa3ce5bd,
+	}
+
+	isa := NewIsaacUnseeded()
+	for i, v := range vectors {
+		if n := isa.Uint32(); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+
+	isa.Seed(1)
+	isa.InitUnseeded()
+	if n := isa.Uint32(); n != vectors[0] {
+		t.Fatalf("%x expected but found %x", vectors[0], n)
+	}
+}
+
+func TestInitValues(t *testing.T) {
+	isa, ref := NewIsaac(), NewIsaac()
+	isa.SetInitValues(goldenRatio32)
+	isa.SeedString("init")
+	ref.SeedString("init")
+	if isa.randrsl != ref.randrsl {
+		t.Fatal("explicit golden ratio differs from the default")
+	}
+
+	isa.SetInitValues([8]uint32{1, 2, 3, 4, 5, 6, 7, 8})
+	isa.SeedString("init")
+	if isa.randrsl == ref.randrsl {
+		t.Fatal("custom initial values have no effect")
+	}
+
+	other := NewIsaac()
+	other.SetInitValues([8]uint32{1, 2, 3, 4, 5, 6, 7, 8})
+	other.SeedString("init")
+	if isa.randrsl != other.randrsl {
+		t.Fatal("custom initial values are not deterministic")
+	}
+}
+
+func TestUint64Order(t *testing.T) {
+	isa := NewIsaac()
+	isa.Seed(1)
+	ref := NewIsaac()
+	ref.Seed(1)
+
+	// Within a block Uint64 equals two Uint32 calls, high half first.
+	for i := 0; i < 128; i++ {
+		hi, lo := ref.Uint32(), ref.Uint32()
+		if n, v := isa.Uint64(), uint64(hi)<<32|uint64(lo); n != v {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+
+	// A single word left at the bottom of a block is skipped.
+	isa.Seed(1)
+	isa.Uint32()
+	for i := 0; i < 127; i++ {
+		isa.Uint64()
+	}
+	block := isa.randrsl
+	n := isa.Uint64()
+	if isa.randrsl == block {
+		t.Fatal("Uint64 did not refill the block")
+	}
+	if v := uint64(isa.randrsl[255])<<32 | uint64(isa.randrsl[254]); n != v {
+		t.Fatalf("%x expected but found %x", v, n)
+	}
+}
+
+func TestUint64Compat(t *testing.T) {
+	isa := NewIsaac()
+	isa.SetUint64Compat(true)
+	isa.Seed(1)
+	ref := NewIsaac()
+	ref.Seed(1)
+
+	// Compat mode straddles blocks after an odd number of words.
+	ref.Uint32()
+	isa.Uint32()
+	for i := 0; i < 300; i++ {
+		hi, lo := ref.Uint32(), ref.Uint32()
+		if n, v := isa.Uint64(), uint64(hi)<<32|uint64(lo); n != v {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+//go:build go1.23
+// +build go1.23
+
+package isaac
+
+import "iter"
+
+// Uint32s returns an endless sequence of values of Uint32.
+func (ctx *Isaac) Uint32s() iter.Seq[uint32] {
+	return seqOf(ctx.Uint32)
+}
+
+// Uint64s returns an endless sequence of values of Uint64.
+func (ctx *Isaac) Uint64s() iter.Seq[uint64] {
+	return seqOf(ctx.Uint64)
+}
+
+// Floats returns an endless sequence of uniform floats in [0, 1), each made
+// from the top 53 bits of Uint64.
+func (ctx *Isaac) Floats() iter.Seq[float64] {
+	return floats(ctx)
+}
+
+// PermSeq returns a lazy random permutation of [0, n). See the function
+// PermSeq.
+func (ctx *Isaac) PermSeq(n int) iter.Seq[int] {
+	return PermSeq(ctx, n)
+}
+
+// Uint32s returns an endless sequence of values of Uint32.
+func (ctx *Isaac64) Uint32s() iter.Seq[uint32] {
+	return seqOf(ctx.Uint32)
+}
+
+// Uint64s returns an endless sequence of values of Uint64.
+func (ctx *Isaac64) Uint64s() iter.Seq[uint64] {
+	return seqOf(ctx.Uint64)
+}
+
+// Floats returns an endless sequence of uniform floats in [0, 1), each made
+// from the top 53 bits of Uint64.
+func (ctx *Isaac64) Floats() iter.Seq[float64] {
+	return floats(ctx)
+}
+
+// PermSeq returns a lazy random permutation of [0, n). See the function
+// PermSeq.
+func (ctx *Isaac64) PermSeq(n int) iter.Seq[int] {
+	return PermSeq(ctx, n)
+}
+
+// PermSeq returns a random permutation of [0, n) drawn from g, which is
+// generated lazily by a Fisher-Yates shuffle over a sparse map: yielding k
+// values costs O(k) time and memory whatever n is. Every iteration over the
+// sequence draws a new permutation. It panics if n is negative.
+func PermSeq(g Generator, n int) iter.Seq[int] {
+	if n < 0 {
+		panic("isaac: invalid argument to PermSeq")
+	}
+
+	return func(yield func(int) bool) {
+		swapped := make(map[int]int)
+		at := func(i int) int {
+			if v, ok := swapped[i]; ok {
+				return v
+			}
+			return i
+		}
+
+		for i := 0; i < n; i++ {
+			j := i + int(Uint64n(g, uint64(n-i)))
+			v := at(j)
+			swapped[j] = at(i)
+			delete(swapped, i)
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Sample returns k elements chosen uniformly without replacement from seq,
+// which must be finite, by reservoir sampling. The elements are in no
+// particular order. If seq has fewer than k elements, all of them are
+// returned. Sample is a function rather than a method because methods
+// cannot have type parameters.
+func Sample[T any](g Generator, seq iter.Seq[T], k int) []T {
+	if k < 0 {
+		panic("isaac: invalid argument to Sample")
+	}
+
+	out := make([]T, 0, k)
+	if k == 0 {
+		return out
+	}
+
+	var i uint64
+	for v := range seq {
+		if len(out) < k {
+			out = append(out, v)
+		} else if j := Uint64n(g, i+1); j < uint64(k) {
+			out[j] = v
+		}
+		i++
+	}
+
+	return out
+}
+
+func seqOf[T any](next func() T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for yield(next()) {
+		}
+	}
+}
+
+func floats(g Generator) iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for yield(float64(g.Uint64()>>11) / (1 << 53)) {
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build go1.23
+// +build go1.23
+
+package isaac
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUint32s(t *testing.T) {
+	isa, ref := NewIsaac(), NewIsaac()
+	isa.Seed(1)
+	ref.Seed(1)
+
+	i := 0
+	for v := range isa.Uint32s() {
+		if n := ref.Uint32(); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i, n, v)
+		}
+		if i++; i == 600 {
+			break
+		}
+	}
+
+	isa64, ref64 := NewIsaac64(), NewIsaac64()
+	isa64.Seed(1)
+	ref64.Seed(1)
+
+	i = 0
+	for v := range isa64.Uint64s() {
+		if n := ref64.Uint64(); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i, n, v)
+		}
+		if i++; i == 600 {
+			break
+		}
+	}
+}
+
+func TestFloats(t *testing.T) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+
+	var sum float64
+	i := 0
+	for f := range isa.Floats() {
+		if f < 0 || f >= 1 {
+			t.Fatalf("[%v] %v out of range", i, f)
+		}
+		sum += f
+		if i++; i == 10000 {
+			break
+		}
+	}
+	if mean := sum / 10000; mean < 0.48 || mean > 0.52 {
+		t.Fatalf("mean %v too far from 0.5", mean)
+	}
+}
+
+func TestPermSeq(t *testing.T) {
+	isa := NewIsaac()
+	isa.Seed(1)
+
+	for _, n := range []int{0, 1, 2, 1000} {
+		perm := slices.Collect(isa.PermSeq(n))
+		if len(perm) != n {
+			t.Fatalf("%v: length %v", n, len(perm))
+		}
+		slices.Sort(perm)
+		for i, v := range perm {
+			if v != i {
+				t.Fatalf("%v: not a permutation", n)
+			}
+		}
+	}
+
+	// Taking a prefix of a huge permutation is cheap.
+	seen := make(map[int]bool)
+	for v := range isa.PermSeq(1 << 30) {
+		if seen[v] || v < 0 || v >= 1<<30 {
+			t.Fatalf("invalid or repeated value %v", v)
+		}
+		if seen[v] = true; len(seen) == 1000 {
+			break
+		}
+	}
+
+	// Position i swaps with i + v % (n - i) for the first accepted Uint64 v.
+	isa.Seed(1)
+	var prefix []int
+	for v := range isa.PermSeq(52) {
+		if prefix = append(prefix, v); len(prefix) == 10 {
+			break
+		}
+	}
+	if want := []int{0, 11, 26, 20, 18, 28, 4, 5, 48, 42}; !slices.Equal(prefix, want) {
+		t.Fatalf("%v expected but found %v", want, prefix)
+	}
+
+	// Every order of three elements is equally likely.
+	count := make(map[[3]int]int)
+	for i := 0; i < 6000; i++ {
+		p := slices.Collect(isa.PermSeq(3))
+		count[[3]int(p)]++
+	}
+	if len(count) != 6 {
+		t.Fatalf("%v orders expected but found %v", 6, len(count))
+	}
+	for p, c := range count {
+		if c < 850 || c > 1150 {
+			t.Fatalf("order %v drawn %v times out of 6000", p, c)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	isa := NewIsaac64()
+	isa.Seed(1)
+
+	all := Sample(isa, slices.Values([]int{1, 2, 3}), 5)
+	slices.Sort(all)
+	if !slices.Equal(all, []int{1, 2, 3}) {
+		t.Fatalf("short sequence: %v", all)
+	}
+	if s := Sample(isa, slices.Values([]int{1, 2, 3}), 0); len(s) != 0 {
+		t.Fatalf("empty sample: %v", s)
+	}
+
+	isa.Seed(1)
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = i
+	}
+	if s, want := Sample(isa, slices.Values(hundred), 5), []int{27, 93, 74, 61, 56}; !slices.Equal(s, want) {
+		t.Fatalf("%v expected but found %v", want, s)
+	}
+
+	// Each of ten elements is in a sample of three with probability 3/10.
+	count := make([]int, 10)
+	for i := 0; i < 10000; i++ {
+		s := Sample(isa, isa.PermSeq(10), 3)
+		if len(s) != 3 {
+			t.Fatalf("sample of %v elements", len(s))
+		}
+		for _, v := range s {
+			count[v]++
+		}
+	}
+	for v, c := range count {
+		if c < 2700 || c > 3300 {
+			t.Fatalf("element %v sampled %v times out of 10000", v, c)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package isaac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// KeyOptions controls how SeedFromKey derives the seed of an instance.
+// The zero value uses plain HKDF-SHA256 without key stretching.
+type KeyOptions struct {
+	// Iterations enables PBKDF2-HMAC-SHA256 stretching of the key with
+	// given iteration count before expansion. Zero disables stretching.
+	Iterations int
+
+	// Info is an optional context string bound into the expansion, so the
+	// same key and salt can derive independent streams.
+	Info []byte
+}
+
+const (
+	kdfLabel32 = "go-isaac/isaac/v1"
+	kdfLabel64 = "go-isaac/isaac64/v1"
+)
+
+// SeedFromKey initializes the state of ISAAC instance using a whole seed,
+// 4 bytes per word of the state (1024 bytes for NewIsaac), expanded from key
+// and salt with HKDF-SHA256. The info of the expansion starts with the label
+// "go-isaac/isaac/v1", which a change of the derivation would have to bump,
+// so a key, salt and options keep giving the same stream.
+//
+// A seed longer than the 8160 bytes HKDF-Expand can produce at once is made
+// of consecutive expansions, each with its index as 4 bytes big-endian
+// appended to the info.
+func (ctx *Isaac) SeedFromKey(key, salt []byte, opts *KeyOptions) {
+	seed := deriveSeed(kdfLabel32, key, salt, opts, ctx.seedSize())
+	ctx.SeedBytes(seed)
+	wipeBytes(seed)
+}
+
+// SeedFromKey initializes the state of ISAAC64 instance using a whole seed,
+// 8 bytes per word of the state (2048 bytes for NewIsaac64), expanded from
+// key and salt with HKDF-SHA256 as for Isaac.SeedFromKey, under the label
+// "go-isaac/isaac64/v1".
+func (ctx *Isaac64) SeedFromKey(key, salt []byte, opts *KeyOptions) {
+	seed := deriveSeed(kdfLabel64, key, salt, opts, ctx.seedSize())
+	ctx.SeedBytes(seed)
+	wipeBytes(seed)
+}
+
+func deriveSeed(label string, key, salt []byte, opts *KeyOptions, size int) []byte {
+	if opts == nil {
+		opts = &KeyOptions{}
+	}
+
+	if opts.Iterations > 0 {
+		key = pbkdf2(key, salt, opts.Iterations, sha256.Size)
+		defer wipeBytes(key)
+	}
+
+	prk := hkdfExtract(salt, key)
+	defer wipeBytes(prk)
+
+	info := append([]byte(label), opts.Info...)
+	if size <= hkdfMaxExpand {
+		return hkdfExpand(prk, info, size)
+	}
+
+	seed := make([]byte, 0, size)
+	info = append(info, 0, 0, 0, 0)
+	for i := uint32(0); len(seed) < size; i++ {
+		binary.BigEndian.PutUint32(info[len(info)-4:], i)
+
+		n := size - len(seed)
+		if n > hkdfMaxExpand {
+			n = hkdfMaxExpand
+		}
+		part := hkdfExpand(prk, info, n)
+		seed = append(seed, part...)
+		wipeBytes(part)
+	}
+
+	return seed
+}
+
+// hkdfExtract implements HKDF-Extract of RFC 5869 with SHA-256.
+func hkdfExtract(salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, sha256.Size)
+	}
+
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// hkdfMaxExpand is the largest output of HKDF-Expand, whose block counter is
+// a single byte.
+const hkdfMaxExpand = 255 * sha256.Size
+
+// hkdfExpand implements HKDF-Expand of RFC 5869 with SHA-256. It panics if
+// size exceeds hkdfMaxExpand.
+func hkdfExpand(prk, info []byte, size int) []byte {
+	if size > hkdfMaxExpand {
+		panic("isaac: HKDF-Expand output longer than 255 blocks")
+	}
+
+	var t []byte
+	out := make([]byte, 0, size+sha256.Size)
+	mac := hmac.New(sha256.New, prk)
+
+	for ctr := byte(1); len(out) < size; ctr++ {
+		mac.Reset()
+		mac.Write(t)
+		mac.Write(info)
+		mac.Write([]byte{ctr})
+		t = mac.Sum(t[:0])
+		out = append(out, t...)
+	}
+
+	return out[:size]
+}
+
+// pbkdf2 implements PBKDF2 of RFC 8018 with HMAC-SHA256.
+func pbkdf2(password, salt []byte, iter, size int) []byte {
+	mac := hmac.New(sha256.New, password)
+	var ctr [4]byte
+	out := make([]byte, 0, size+sha256.Size)
+	u := make([]byte, 0, sha256.Size)
+	t := make([]byte, sha256.Size)
+
+	for block := uint32(1); len(out) < size; block++ {
+		binary.BigEndian.PutUint32(ctr[:], block)
+		mac.Reset()
+		mac.Write(salt)
+		mac.Write(ctr[:])
+		u = mac.Sum(u[:0])
+		copy(t, u)
+
+		for i := 1; i < iter; i++ {
+			mac.Reset()
+			mac.Write(u)
+			u = mac.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+
+		out = append(out, t...)
+	}
+
+	return out[:size]
+}
@@ -0,0 +1,204 @@
+package isaac
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestHKDF(t *testing.T) {
+	// RFC 5869 test case 1
+	ikm := mustHex(t, "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt := mustHex(t, "000102030405060708090a0b0c")
+	info := mustHex(t, "f0f1f2f3f4f5f6f7f8f9")
+	prk := mustHex(t, "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5")
+	okm := mustHex(t, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865")
+
+	if got := hkdfExtract(salt, ikm); !bytes.Equal(got, prk) {
+		t.Fatalf("prk %x != %x", got, prk)
+	}
+	if got := hkdfExpand(prk, info, len(okm)); !bytes.Equal(got, okm) {
+		t.Fatalf("okm %x != %x", got, okm)
+	}
+
+	// The block counter is a single byte, so the output is limited to 255
+	// blocks.
+	if got := hkdfExpand(prk, info, hkdfMaxExpand); len(got) != hkdfMaxExpand {
+		t.Fatalf("expanded %v bytes", len(got))
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	hkdfExpand(prk, info, hkdfMaxExpand+1)
+}
+
+func TestPBKDF2(t *testing.T) {
+	// RFC 7914 section 11
+	vectors := []struct {
+		password, salt string
+		iter           int
+		dk             string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+	}
+
+	for _, v := range vectors {
+		dk := mustHex(t, v.dk)
+		if got := pbkdf2([]byte(v.password), []byte(v.salt), v.iter, len(dk)); !bytes.Equal(got, dk) {
+			t.Fatalf("pbkdf2(%q, %q, %v) %x != %x", v.password, v.salt, v.iter, got, dk)
+		}
+	}
+}
+
+func TestSeedFromKey(t *testing.T) {
+	key, salt := []byte("hunter2"), []byte("room-42")
+
+	vectors := []uint32{
+		0xa55c0ff6, 0x45c0da2a, 0xc147d93e, 0x2882d67f, 0x930388bd, 0xf141deb6, 0x08bf7583, 0x02ab2c60,
+	}
+	stretched := []uint32{
+		0xc7355e9b, 0x45d7cef8, 0x0fd9a29a, 0xe98b26d4, 0xf01323b8, 0x774a3b72, 0x7490ef1f, 0x9129f6c6,
+	}
+
+	isa := NewIsaac()
+	isa.SeedFromKey(key, salt, nil)
+	for i, v := range vectors {
+		if n := isa.Uint32(); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+
+	isa = NewIsaac()
+	isa.SeedFromKey(key, salt, &KeyOptions{Iterations: 1000})
+	for i, v := range stretched {
+		if n := isa.Uint32(); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+}
+
+func TestIsaac64SeedFromKey(t *testing.T) {
+	key, salt := []byte("hunter2"), []byte("room-42")
+
+	vectors := []uint64{
+		0xfdb2b30ad9eef390, 0x9bc11e4aaff709cb, 0x2a5bcd8bed99854b, 0x091bdc4e9e9218eb,
+	}
+	stretched := []uint64{
+		0xdd6dc2f8e4674012, 0x10c0f2da8eb53fc0, 0x9b178d6a003ef70a, 0x82ac511be94ed1c0,
+	}
+
+	isa := NewIsaac64()
+	isa.SeedFromKey(key, salt, nil)
+	for i, v := range vectors {
+		if n := isa.Uint64(); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+
+	isa = NewIsaac64()
+	isa.SeedFromKey(key, salt, &KeyOptions{Iterations: 1000, Info: []byte("x")})
+	for i, v := range stretched {
+		if n := isa.Uint64(); v != n {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+}
+
+// TestSeedFromKeySizes pins the seeds derived for other state sizes, whose
+// hashes were computed with an independent HKDF implementation, and the
+// first outputs of instances seeded with them.
+func TestSeedFromKeySizes(t *testing.T) {
+	key, salt := []byte("hunter2"), []byte("room-42")
+	opts := &KeyOptions{Info: []byte("x")}
+
+	seeds := []struct {
+		label string
+		size  int
+		hash  string
+	}{
+		{kdfLabel32, 4 << 3, "3dad40cba2fb7c2e22c9ae7dcb45208676fc4941d7c577d62f9c2fd3c3c1e008"},
+		{kdfLabel32, 4 << 10, "506ff6992ca9101b15182d9ecac46ea823420955a7858f56df50ed7ff0075a5e"},
+		{kdfLabel64, 8 << 10, "52846304d399c70b45f3d9171e391563c081d76399f4a8e27e2c93fb27416f5d"},
+		{kdfLabel32, 4 << 16, "3ff8ef031bfef842aa55fea257c032d5e2feeb49eda5e32e128917cc7260c8be"},
+		{kdfLabel64, 8 << 16, "2dc1826bf15801115b89368a2238f2bb8c95cc10de8ae267ba8340c7d6d7203c"},
+	}
+	for _, sv := range seeds {
+		sum := sha256.Sum256(deriveSeed(sv.label, key, salt, opts, sv.size))
+		if got := hex.EncodeToString(sum[:]); got != sv.hash {
+			t.Errorf("%s %v bytes: seed hash %s, expected %s", sv.label, sv.size, got, sv.hash)
+		}
+	}
+
+	outputs := []struct {
+		log2 int
+		v32  []uint32
+		v64  []uint64
+	}{
+		{3, []uint32{0xf2cf44f6, 0xde5eda0b, 0x2ebe4fda, 0x4d530cf7}, []uint64{0x0447f4f0e1f7ba64, 0x52c337501885c6de}},
+		{10, []uint32{0x4ba083fb, 0x0099a170, 0x29c9d90f, 0x1808b6bf}, []uint64{0x558a3c097d236cc3, 0xaabcdf6594db51d5}},
+		{16, []uint32{0x24f5ea1e, 0xc557b5cd, 0xb782f48a, 0x5ae0d734}, []uint64{0xb63344eb3d10b85f, 0x1816224fb08542d5}},
+	}
+	for _, ov := range outputs {
+		isa := NewIsaacSize(ov.log2)
+		isa.SeedFromKey(key, salt, opts)
+		for i, v := range ov.v32 {
+			if n := isa.Uint32(); v != n {
+				t.Fatalf("2^%v [%v] %x expected but found %x", ov.log2, i, v, n)
+			}
+		}
+
+		isa64 := NewIsaac64Size(ov.log2)
+		isa64.SeedFromKey(key, salt, opts)
+		for i, v := range ov.v64 {
+			if n := isa64.Uint64(); v != n {
+				t.Fatalf("2^%v [%v] %x expected but found %x", ov.log2, i, v, n)
+			}
+		}
+	}
+}
+
+func TestSeedFromKeyReseed(t *testing.T) {
+	key, salt := []byte("hunter2"), []byte("room-42")
+	opts := &KeyOptions{Iterations: 10}
+
+	used, fresh := NewIsaac(), NewIsaac()
+	used.Seed(1)
+	for i := 0; i < 300; i++ {
+		used.Uint32()
+	}
+	used.SeedFromKey(key, salt, opts)
+	fresh.SeedFromKey(key, salt, opts)
+	for i := 0; i < 600; i++ {
+		if x, y := used.Uint32(), fresh.Uint32(); x != y {
+			t.Fatalf("[%v] reseeded %x, new instance %x", i, x, y)
+		}
+	}
+
+	used64, fresh64 := NewIsaac64(), NewIsaac64()
+	used64.Seed(1)
+	for i := 0; i < 300; i++ {
+		used64.Uint64()
+	}
+	used64.SeedFromKey(key, salt, opts)
+	fresh64.SeedFromKey(key, salt, opts)
+	for i := 0; i < 600; i++ {
+		if x, y := used64.Uint64(), fresh64.Uint64(); x != y {
+			t.Fatalf("[%v] reseeded %x, new instance %x", i, x, y)
+		}
+	}
+}
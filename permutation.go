@@ -0,0 +1,105 @@
+package isaac
+
+import "math/bits"
+
+// permutationRounds is the number of Feistel rounds of Permutation.
+const permutationRounds = 8
+
+// Permutation is a keyed pseudorandom permutation of [0, n) for any n up to
+// 2^64, which maps an index to its position without storing the permutation.
+// It is a balanced Feistel network over the smallest even number of bits
+// that covers n, with cycle walking to stay within [0, n), so it is exactly
+// bijective. Each index costs a few rounds of mixing, four times as many at
+// worst. It is meant for shuffling huge ranges reproducibly, not as a
+// cipher.
+type Permutation struct {
+	n    uint64
+	half uint
+	mask uint64
+	keys [permutationRounds]uint64
+}
+
+// NewPermutation returns a permutation of [0, n) whose round keys are the
+// next eight values of ctx.Uint64. n = 0 stands for 2^64.
+func NewPermutation(n uint64, ctx *Isaac64) *Permutation {
+	w := uint(64)
+	if n != 0 {
+		w = uint(bits.Len64(n - 1))
+	}
+
+	p := &Permutation{n: n, half: (w + 1) / 2}
+	if p.half == 0 {
+		p.half = 1
+	}
+	p.mask = 1<<p.half - 1
+	for i := range p.keys {
+		p.keys[i] = ctx.Uint64()
+	}
+
+	return p
+}
+
+// Len returns n, or 0 for a permutation of 2^64 elements.
+func (p *Permutation) Len() uint64 {
+	return p.n
+}
+
+// Index returns the position of i in the permutation. It panics if i is not
+// in [0, n).
+func (p *Permutation) Index(i uint64) uint64 {
+	p.check(i)
+	for {
+		i = p.encrypt(i)
+		if p.n == 0 || i < p.n {
+			return i
+		}
+	}
+}
+
+// Inverse returns the element at position j, so that Inverse(Index(i)) == i.
+// It panics if j is not in [0, n).
+func (p *Permutation) Inverse(j uint64) uint64 {
+	p.check(j)
+	for {
+		j = p.decrypt(j)
+		if p.n == 0 || j < p.n {
+			return j
+		}
+	}
+}
+
+func (p *Permutation) check(i uint64) {
+	if p.n != 0 && i >= p.n {
+		panic("isaac: index out of range of permutation")
+	}
+}
+
+func (p *Permutation) encrypt(x uint64) uint64 {
+	l, r := x>>p.half, x&p.mask
+	for _, k := range p.keys {
+		l, r = r, l^p.round(k, r)
+	}
+
+	return l<<p.half | r
+}
+
+func (p *Permutation) decrypt(x uint64) uint64 {
+	l, r := x>>p.half, x&p.mask
+	for i := len(p.keys) - 1; i >= 0; i-- {
+		l, r = r^p.round(p.keys[i], l), l
+	}
+
+	return l<<p.half | r
+}
+
+// round is the finalizer of MurmurHash3 applied to the half block and the
+// round key.
+func (p *Permutation) round(k, x uint64) uint64 {
+	x ^= k
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x & p.mask
+}
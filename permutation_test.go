@@ -0,0 +1,86 @@
+package isaac
+
+import "testing"
+
+func TestPermutation(t *testing.T) {
+	ctx := NewIsaac64()
+	ctx.Seed(1)
+
+	for _, n := range []uint64{1, 2, 3, 5, 16, 17, 1000, 1 << 16, 1<<16 + 1} {
+		p := NewPermutation(n, ctx)
+		seen := make([]bool, n)
+		for i := uint64(0); i < n; i++ {
+			j := p.Index(i)
+			if j >= n || seen[j] {
+				t.Fatalf("%v: Index(%v) = %v is out of range or repeated", n, i, j)
+			}
+			seen[j] = true
+			if k := p.Inverse(j); k != i {
+				t.Fatalf("%v: Inverse(%v) = %v, %v expected", n, j, k, i)
+			}
+		}
+	}
+
+	for _, n := range []uint64{0, 1<<63 + 12345, 1<<64 - 1} {
+		p := NewPermutation(n, ctx)
+		for i := 0; i < 10000; i++ {
+			x := ctx.Uint64()
+			if n != 0 {
+				x %= n
+			}
+			j := p.Index(x)
+			if n != 0 && j >= n {
+				t.Fatalf("%v: Index(%v) = %v is out of range", n, x, j)
+			}
+			if k := p.Inverse(j); k != x {
+				t.Fatalf("%v: Inverse(%v) = %v, %v expected", n, j, k, x)
+			}
+		}
+	}
+}
+
+func TestPermutationKeys(t *testing.T) {
+	a, b := NewIsaac64(), NewIsaac64()
+	a.Seed(1)
+	b.Seed(1)
+	p, q := NewPermutation(1000, a), NewPermutation(1000, b)
+	r := NewPermutation(1000, a)
+
+	same, fixed := true, 0
+	for i := uint64(0); i < 1000; i++ {
+		if p.Index(i) != q.Index(i) {
+			t.Fatalf("Index(%v) differs for the same seed", i)
+		}
+		if p.Index(i) != r.Index(i) {
+			same = false
+		}
+		if p.Index(i) == i {
+			fixed++
+		}
+	}
+	if same {
+		t.Fatal("different keys give the same permutation")
+	}
+	// A random permutation has one fixed point on average.
+	if fixed > 10 {
+		t.Fatalf("%v fixed points", fixed)
+	}
+
+	// Over many keys every position is equally likely.
+	count := make([]int, 10)
+	for i := 0; i < 10000; i++ {
+		count[NewPermutation(10, a).Index(3)]++
+	}
+	for j, c := range count {
+		if c < 850 || c > 1150 {
+			t.Fatalf("position %v drawn %v times out of 10000", j, c)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	p.Index(1000)
+}
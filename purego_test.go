@@ -0,0 +1,85 @@
+package isaac
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func goTool(t *testing.T, env []string, args ...string) string {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping go tool invocation in short mode")
+	}
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	cmd := exec.Command(gobin, args...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+
+	return string(out)
+}
+
+func TestPuregoSuite(t *testing.T) {
+	if puregoBuild {
+		t.Skip("already running with purego tag")
+	}
+
+	goTool(t, nil, "test", "-count=1", "-tags", "purego", ".")
+}
+
+func TestPuregoNoUnsafe(t *testing.T) {
+	out := goTool(t, nil, "list", "-tags", "purego", "-f", "{{.Imports}}", ".")
+	if strings.Contains(out, "unsafe") {
+		t.Fatalf("purego build imports unsafe: %s", out)
+	}
+}
+
+// TestBigEndianPurego checks that big-endian targets decode seeds with
+// encoding/binary, so that a seed gives the same stream with or without the
+// purego tag.
+func TestBigEndianPurego(t *testing.T) {
+	for _, arch := range []string{"s390x", "ppc64", "mips"} {
+		out := goTool(t, []string{"GOOS=linux", "GOARCH=" + arch}, "list", "-f", "{{.GoFiles}}", ".")
+		if !strings.Contains(out, "copy_purego.go") || strings.Contains(out, "copy_unsafe.go") {
+			t.Fatalf("%s builds %s", arch, out)
+		}
+	}
+}
+
+func TestCrossCompile(t *testing.T) {
+	targets := [][]string{
+		{"GOOS=js", "GOARCH=wasm"},
+		{"GOOS=wasip1", "GOARCH=wasm"},
+		{"GOOS=linux", "GOARCH=s390x"},
+	}
+
+	for _, env := range targets {
+		env := append(env, "CGO_ENABLED=0")
+		t.Run(strings.Join(env[:2], ","), func(t *testing.T) {
+			goTool(t, env, "vet", "-tags", "purego", "./...")
+			goTool(t, env, "vet", "./...")
+		})
+	}
+}
+
+// TestRaceSuite runs the suite under the race detector, which also turns on
+// checkptr and so catches unsafe pointer arithmetic leaving an allocation.
+func TestRaceSuite(t *testing.T) {
+	if out := goTool(t, nil, "env", "CGO_ENABLED"); strings.TrimSpace(out) != "1" {
+		t.Skip("race detector needs cgo")
+	}
+
+	for _, tags := range []string{"", "purego"} {
+		goTool(t, nil, "test", "-count=1", "-short", "-race", "-tags", tags, ".")
+	}
+}
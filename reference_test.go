@@ -0,0 +1,151 @@
+//go:build cgo && isaacref
+// +build cgo,isaacref
+
+package isaac
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/skdltmxn/go-isaac/reftest"
+)
+
+// referenceRuns returns the number of random seeds and words per seed
+// checked against the reference implementations.
+func referenceRuns() (int, int) {
+	if testing.Short() {
+		return 16, 1 << 12
+	}
+
+	return 64, 1 << 16
+}
+
+// referenceRand returns a random source whose seed is logged, so that a
+// failure can be replayed.
+func referenceRand(t *testing.T) *rand.Rand {
+	seed := time.Now().UnixNano()
+	t.Logf("random seed %d", seed)
+
+	return rand.New(rand.NewSource(seed))
+}
+
+// randomSeed returns a seed of random length, mostly full or empty.
+func randomSeed(r *rand.Rand, max int) []byte {
+	n := r.Intn(max + 1)
+	switch r.Intn(4) {
+	case 0:
+		n = max
+	case 1:
+		n = 0
+	}
+
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
+
+func TestReferenceIsaac(t *testing.T) {
+	r := referenceRand(t)
+	seeds, words := referenceRuns()
+
+	for s := 0; s < seeds; s++ {
+		seed := randomSeed(r, 1024)
+		padded := make([]byte, 1024)
+		copy(padded, seed)
+
+		var ref [256]uint32
+		for i := range ref {
+			ref[i] = binary.LittleEndian.Uint32(padded[i*4:])
+		}
+
+		for _, seeded := range []bool{true, false} {
+			isa := NewIsaacUnseeded()
+			if seeded {
+				isa.SeedBytes(seed)
+			}
+			reftest.RandInit(&ref, seeded)
+			reftest.ReadableInit(&ref, seeded)
+
+			for i := 0; i < words; i++ {
+				n, want, readable := isa.Uint32(), reftest.Rand(), reftest.Readable()
+				if n != want || n != readable {
+					t.Fatalf("seed %x flag %v [%v]: %x, rand.c %x, readable.c %x", seed, seeded, i, n, want, readable)
+				}
+			}
+		}
+	}
+}
+
+func TestReferenceIsaac64(t *testing.T) {
+	r := referenceRand(t)
+	seeds, words := referenceRuns()
+
+	for s := 0; s < seeds; s++ {
+		seed := randomSeed(r, 2048)
+		padded := make([]byte, 2048)
+		copy(padded, seed)
+
+		var ref [256]uint64
+		for i := range ref {
+			ref[i] = binary.LittleEndian.Uint64(padded[i*8:])
+		}
+
+		for _, seeded := range []bool{true, false} {
+			isa := NewIsaac64Unseeded()
+			if seeded {
+				isa.SeedBytes(seed)
+			}
+			reftest.Isaac64Init(&ref, seeded)
+
+			for i := 0; i < words; i++ {
+				if n, want := isa.Uint64(), reftest.Isaac64(); n != want {
+					t.Fatalf("seed %x flag %v [%v]: %x, isaac64.c %x", seed, seeded, i, n, want)
+				}
+			}
+		}
+	}
+}
+
+// TestReferenceBlocks compares whole blocks, which covers the index wrap of
+// the main loop independently of the output order.
+func TestReferenceBlocks(t *testing.T) {
+	r := referenceRand(t)
+
+	var seed [256]uint32
+	for i := range seed {
+		seed[i] = r.Uint32()
+	}
+
+	isa := NewIsaac()
+	isa.randrsl = seed
+	isa.randInit(true)
+	reftest.RandInit(&seed, true)
+	reftest.ReadableInit(&seed, true)
+	for i := 0; i < 1024; i++ {
+		isa.isaac()
+		if b := reftest.RandBlock(); isa.randrsl != b {
+			t.Fatalf("block %v differs from rand.c", i)
+		}
+		if b := reftest.ReadableBlock(); isa.randrsl != b {
+			t.Fatalf("block %v differs from readable.c", i)
+		}
+	}
+
+	var seed64 [256]uint64
+	for i := range seed64 {
+		seed64[i] = r.Uint64()
+	}
+
+	isa64 := NewIsaac64()
+	isa64.randrsl = seed64
+	isa64.randInit(true)
+	reftest.Isaac64Init(&seed64, true)
+	for i := 0; i < 1024; i++ {
+		isa64.isaac64()
+		if b := reftest.Isaac64Block(); isa64.randrsl != b {
+			t.Fatalf("block %v differs from isaac64.c", i)
+		}
+	}
+}
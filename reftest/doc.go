@@ -0,0 +1,17 @@
+// Copyright 2021 skdltmxn. All rights reserved.
+//
+// doc.go
+//
+// Reference implementations of ISAAC by Bob Jenkins
+
+// Package reftest binds Bob Jenkins' public domain reference
+// implementations of ISAAC (rand.c and readable.c) and ISAAC64 (isaac64.c)
+// through cgo for differential testing of package isaac.
+//
+// The package is only built with cgo and the isaacref build tag:
+//
+//	go test -tags isaacref . ./reftest
+//
+// The reference implementations keep their state in globals, so the
+// functions of this package must not be used concurrently.
+package reftest
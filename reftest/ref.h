@@ -0,0 +1,18 @@
+#ifndef REFTEST_H
+#define REFTEST_H
+
+#include <stdint.h>
+
+void ref_rand_init(const uint32_t *seed, int flag);
+void ref_rand_block(uint32_t *out);
+uint32_t ref_rand_next(void);
+
+void ref_readable_init(const uint32_t *seed, int flag);
+void ref_readable_block(uint32_t *out);
+uint32_t ref_readable_next(void);
+
+void ref_isaac64_init(const uint64_t *seed, int flag);
+void ref_isaac64_block(uint64_t *out);
+uint64_t ref_isaac64_next(void);
+
+#endif
@@ -0,0 +1,26 @@
+//go:build cgo && isaacref
+
+#include <string.h>
+
+#define randinit isaac64_randinit
+#define randrsl isaac64_randrsl
+#define randcnt isaac64_randcnt
+#include "csrc/isaac64.c"
+#include "ref.h"
+
+void ref_isaac64_init(const uint64_t *seed, int flag)
+{
+  memcpy(randrsl, seed, sizeof(randrsl));
+  randinit(flag);
+}
+
+void ref_isaac64_block(uint64_t *out)
+{
+  isaac64();
+  memcpy(out, randrsl, sizeof(randrsl));
+}
+
+uint64_t ref_isaac64_next(void)
+{
+  return rand();
+}
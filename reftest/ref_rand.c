@@ -0,0 +1,24 @@
+//go:build cgo && isaacref
+
+#include <string.h>
+#include "csrc/rand.c"
+#include "ref.h"
+
+static randctx ctx;
+
+void ref_rand_init(const uint32_t *seed, int flag)
+{
+  memcpy(ctx.randrsl, seed, sizeof(ctx.randrsl));
+  randinit(&ctx, flag);
+}
+
+void ref_rand_block(uint32_t *out)
+{
+  isaac(&ctx);
+  memcpy(out, ctx.randrsl, sizeof(ctx.randrsl));
+}
+
+uint32_t ref_rand_next(void)
+{
+  return rand(&ctx);
+}
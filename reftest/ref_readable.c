@@ -0,0 +1,34 @@
+//go:build cgo && isaacref
+
+#include <string.h>
+
+#define isaac readable_isaac
+#define randinit readable_randinit
+#define randrsl readable_randrsl
+#define randcnt readable_randcnt
+#define main readable_main
+#include "csrc/readable.c"
+#include "ref.h"
+
+void ref_readable_init(const uint32_t *seed, int flag)
+{
+  memcpy(randrsl, seed, sizeof(randrsl));
+  randinit(flag);
+}
+
+void ref_readable_block(uint32_t *out)
+{
+  isaac();
+  memcpy(out, randrsl, sizeof(randrsl));
+}
+
+/* readable.c has no rand() macro, this mirrors the one of rand.h. */
+uint32_t ref_readable_next(void)
+{
+  if (!randcnt--)
+  {
+    isaac();
+    randcnt = 255;
+  }
+  return randrsl[randcnt];
+}
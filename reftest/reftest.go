@@ -0,0 +1,67 @@
+//go:build cgo && isaacref
+// +build cgo,isaacref
+
+package reftest
+
+// #cgo CFLAGS: -O2 -w
+// #include "ref.h"
+import "C"
+
+import "unsafe"
+
+func flag(seeded bool) C.int {
+	if seeded {
+		return 1
+	}
+
+	return 0
+}
+
+// RandInit copies seed into randrsl and calls randinit of rand.c.
+func RandInit(seed *[256]uint32, seeded bool) {
+	C.ref_rand_init((*C.uint32_t)(unsafe.Pointer(&seed[0])), flag(seeded))
+}
+
+// RandBlock calls isaac of rand.c and returns randrsl.
+func RandBlock() (out [256]uint32) {
+	C.ref_rand_block((*C.uint32_t)(unsafe.Pointer(&out[0])))
+	return
+}
+
+// Rand returns the next value of the rand macro of rand.h.
+func Rand() uint32 {
+	return uint32(C.ref_rand_next())
+}
+
+// ReadableInit copies seed into randrsl and calls randinit of readable.c.
+func ReadableInit(seed *[256]uint32, seeded bool) {
+	C.ref_readable_init((*C.uint32_t)(unsafe.Pointer(&seed[0])), flag(seeded))
+}
+
+// ReadableBlock calls isaac of readable.c and returns randrsl.
+func ReadableBlock() (out [256]uint32) {
+	C.ref_readable_block((*C.uint32_t)(unsafe.Pointer(&out[0])))
+	return
+}
+
+// Readable returns the next value of readable.c in the order of the rand
+// macro of rand.h.
+func Readable() uint32 {
+	return uint32(C.ref_readable_next())
+}
+
+// Isaac64Init copies seed into randrsl and calls randinit of isaac64.c.
+func Isaac64Init(seed *[256]uint64, seeded bool) {
+	C.ref_isaac64_init((*C.uint64_t)(unsafe.Pointer(&seed[0])), flag(seeded))
+}
+
+// Isaac64Block calls isaac64 of isaac64.c and returns randrsl.
+func Isaac64Block() (out [256]uint64) {
+	C.ref_isaac64_block((*C.uint64_t)(unsafe.Pointer(&out[0])))
+	return
+}
+
+// Isaac64 returns the next value of the rand macro of isaac64.h.
+func Isaac64() uint64 {
+	return uint64(C.ref_isaac64_next())
+}
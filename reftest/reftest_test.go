@@ -0,0 +1,83 @@
+//go:build cgo && isaacref
+// +build cgo,isaacref
+
+package reftest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/skdltmxn/go-isaac/internal/vectorfile"
+)
+
+// readVectors returns the words of a testdata vector file.
+func readVectors(t *testing.T, name, alg string) []uint64 {
+	t.Helper()
+
+	f, err := os.Open("../testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	vf, err := vectorfile.Parse(f, alg)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+
+	return vf.Words
+}
+
+func TestRandvect(t *testing.T) {
+	var seed [256]uint32
+	want := readVectors(t, "randvect.txt", "isaac")
+
+	for name, impl := range map[string]struct {
+		init  func(*[256]uint32, bool)
+		block func() [256]uint32
+	}{
+		"rand.c":     {RandInit, RandBlock},
+		"readable.c": {ReadableInit, ReadableBlock},
+	} {
+		var got []uint64
+		impl.init(&seed, true)
+		for i := 0; i < 2; i++ {
+			for _, v := range impl.block() {
+				got = append(got, uint64(v))
+			}
+		}
+
+		if !equalWords(got, want) {
+			t.Fatalf("%s does not reproduce randvect.txt", name)
+		}
+	}
+}
+
+func TestIsaac64Randvect(t *testing.T) {
+	var seed [256]uint64
+	var got []uint64
+	want := readVectors(t, "randvect64.txt", "isaac64")
+
+	Isaac64Init(&seed, true)
+	for i := 0; i < 2; i++ {
+		block := Isaac64Block()
+		got = append(got, block[:]...)
+	}
+
+	if !equalWords(got, want) {
+		t.Fatal("isaac64.c does not reproduce the reference vectors")
+	}
+}
+
+func equalWords(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
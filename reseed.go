@@ -0,0 +1,241 @@
+package isaac
+
+import (
+	"crypto/rand"
+	"io"
+	"time"
+)
+
+// ReseedPolicy describes when a reseeding generator mixes fresh entropy into
+// its state. At least one of Words and Interval should be set, otherwise the
+// generator never reseeds on its own.
+type ReseedPolicy struct {
+	// Words is the number of generated words after which the state is
+	// reseeded. Zero disables the limit.
+	Words uint64
+
+	// Interval is the duration after which the state is reseeded. It is
+	// checked on every output. Zero disables the limit.
+	Interval time.Duration
+
+	// Source supplies fresh entropy. If nil, crypto/rand.Reader is used.
+	Source io.Reader
+
+	// OnReseed, if not nil, is called after every reseed attempt. Failed
+	// attempts are also reported by Err of the generator.
+	OnReseed func(ReseedEvent)
+}
+
+// ReseedEvent describes a reseed attempt reported to ReseedPolicy.OnReseed.
+type ReseedEvent struct {
+	// Words is the number of words generated since the previous reseed.
+	Words uint64
+
+	// Elapsed is the time passed since the previous reseed.
+	Elapsed time.Duration
+
+	// Err is the error returned by the entropy source, if any. The
+	// generator keeps running on its previous state when it is not nil.
+	Err error
+}
+
+type reseedable interface {
+	reseedFrom(r io.Reader) error
+}
+
+type reseeder struct {
+	policy ReseedPolicy
+	words  uint64
+	last   time.Time
+	now    func() time.Time
+	err    error
+}
+
+func newReseeder(policy ReseedPolicy) reseeder {
+	if policy.Source == nil {
+		policy.Source = rand.Reader
+	}
+
+	return reseeder{
+		policy: policy,
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+func (s *reseeder) tick(ctx reseedable, n uint64) {
+	due := s.policy.Words > 0 && s.words >= s.policy.Words
+	if !due && s.policy.Interval > 0 {
+		due = s.now().Sub(s.last) >= s.policy.Interval
+	}
+	if due {
+		s.reseed(ctx)
+	}
+
+	s.words += n
+}
+
+// tickBits is tick for a draw of k bits from r, which holds bits of words of
+// the given width. A reseed drops the buffered bits, so the words the draw
+// takes are counted after it.
+func (s *reseeder) tickBits(ctx reseedable, r *bitReservoir, k, width uint) {
+	s.tick(ctx, 0)
+	s.words += r.words(k, width)
+}
+
+func (s *reseeder) reseed(ctx reseedable) error {
+	now := s.now()
+	err := ctx.reseedFrom(s.policy.Source)
+
+	if s.policy.OnReseed != nil {
+		s.policy.OnReseed(ReseedEvent{
+			Words:   s.words,
+			Elapsed: now.Sub(s.last),
+			Err:     err,
+		})
+	}
+
+	// A failed attempt is retried once the policy triggers again, rather
+	// than on every following output.
+	s.words = 0
+	s.last = now
+	s.err = err
+	return err
+}
+
+// Err returns the error of the last reseed attempt, or nil if it succeeded
+// or there was none. After a failed attempt the generator keeps running on
+// its previous state until a later attempt succeeds.
+func (s *reseeder) Err() error {
+	return s.err
+}
+
+// ReseedingIsaac wraps ISAAC instance and periodically mixes fresh entropy
+// into its state according to a ReseedPolicy.
+type ReseedingIsaac struct {
+	ctx *Isaac
+	reseeder
+}
+
+// NewReseedingIsaac returns a new reseeding wrapper around ctx, which
+// should already be seeded.
+func NewReseedingIsaac(ctx *Isaac, policy ReseedPolicy) *ReseedingIsaac {
+	return &ReseedingIsaac{ctx: ctx, reseeder: newReseeder(policy)}
+}
+
+// Reseed immediately mixes fresh entropy into the state.
+func (r *ReseedingIsaac) Reseed() error {
+	return r.reseed(r.ctx)
+}
+
+// Int63 returns a non-negative 63-bit integer as an int64.
+func (r *ReseedingIsaac) Int63() int64 {
+	r.tick(r.ctx, 2)
+	return r.ctx.Int63()
+}
+
+// Uint32 returns a random 32-bit unsigned integer.
+func (r *ReseedingIsaac) Uint32() uint32 {
+	r.tick(r.ctx, 1)
+	return r.ctx.Uint32()
+}
+
+// Uint64 returns a random 64-bit unsigned integer.
+func (r *ReseedingIsaac) Uint64() uint64 {
+	r.tick(r.ctx, 2)
+	return r.ctx.Uint64()
+}
+
+// Int31 returns a non-negative 31-bit integer as an int32.
+func (r *ReseedingIsaac) Int31() int32 {
+	r.tick(r.ctx, 2)
+	return r.ctx.Int31()
+}
+
+// Int returns a non-negative integer as an int
+func (r *ReseedingIsaac) Int() int {
+	r.tick(r.ctx, 2)
+	return r.ctx.Int()
+}
+
+// Bool returns a random bit as a bool, like Bool of the wrapped instance.
+func (r *ReseedingIsaac) Bool() bool {
+	r.tickBits(r.ctx, &r.ctx.resv, 1, 32)
+	return r.ctx.Bool()
+}
+
+// Bits returns n random bits, 0 <= n <= 64, like Bits of the wrapped
+// instance. It panics if n is out of range.
+func (r *ReseedingIsaac) Bits(n int) uint64 {
+	if n < 0 || n > 64 {
+		panic("isaac: invalid argument to Bits")
+	}
+
+	r.tickBits(r.ctx, &r.ctx.resv, uint(n), 32)
+	return r.ctx.Bits(n)
+}
+
+// ReseedingIsaac64 wraps ISAAC64 instance and periodically mixes fresh
+// entropy into its state according to a ReseedPolicy.
+type ReseedingIsaac64 struct {
+	ctx *Isaac64
+	reseeder
+}
+
+// NewReseedingIsaac64 returns a new reseeding wrapper around ctx, which
+// should already be seeded.
+func NewReseedingIsaac64(ctx *Isaac64, policy ReseedPolicy) *ReseedingIsaac64 {
+	return &ReseedingIsaac64{ctx: ctx, reseeder: newReseeder(policy)}
+}
+
+// Reseed immediately mixes fresh entropy into the state.
+func (r *ReseedingIsaac64) Reseed() error {
+	return r.reseed(r.ctx)
+}
+
+// Int63 returns a non-negative 63-bit integer as an int64.
+func (r *ReseedingIsaac64) Int63() int64 {
+	r.tick(r.ctx, 1)
+	return r.ctx.Int63()
+}
+
+// Uint32 returns a random 32-bit unsigned integer.
+func (r *ReseedingIsaac64) Uint32() uint32 {
+	r.tick(r.ctx, 1)
+	return r.ctx.Uint32()
+}
+
+// Uint64 returns a random 64-bit unsigned integer.
+func (r *ReseedingIsaac64) Uint64() uint64 {
+	r.tick(r.ctx, 1)
+	return r.ctx.Uint64()
+}
+
+// Int31 returns a non-negative 31-bit integer as an int32.
+func (r *ReseedingIsaac64) Int31() int32 {
+	r.tick(r.ctx, 1)
+	return r.ctx.Int31()
+}
+
+// Int returns a non-negative integer as an int
+func (r *ReseedingIsaac64) Int() int {
+	r.tick(r.ctx, 1)
+	return r.ctx.Int()
+}
+
+// Bool returns a random bit as a bool, like Bool of the wrapped instance.
+func (r *ReseedingIsaac64) Bool() bool {
+	r.tickBits(r.ctx, &r.ctx.resv, 1, 64)
+	return r.ctx.Bool()
+}
+
+// Bits returns n random bits, 0 <= n <= 64, like Bits of the wrapped
+// instance. It panics if n is out of range.
+func (r *ReseedingIsaac64) Bits(n int) uint64 {
+	if n < 0 || n > 64 {
+		panic("isaac: invalid argument to Bits")
+	}
+
+	r.tickBits(r.ctx, &r.ctx.resv, uint(n), 64)
+	return r.ctx.Bits(n)
+}
@@ -0,0 +1,159 @@
+package isaac
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestReseedingWords(t *testing.T) {
+	var events []ReseedEvent
+	isa := NewIsaac()
+	isa.Seed(7)
+	ref := NewIsaac()
+	ref.Seed(7)
+
+	r := NewReseedingIsaac(isa, ReseedPolicy{
+		Words:    300,
+		Source:   zeroReader{},
+		OnReseed: func(e ReseedEvent) { events = append(events, e) },
+	})
+
+	for i := 0; i < 300; i++ {
+		if r.Uint32() != ref.Uint32() {
+			t.Fatalf("[%v] output differs before the first reseed", i)
+		}
+	}
+	if len(events) != 0 {
+		t.Fatalf("reseeded too early: %v", events)
+	}
+
+	if err := ref.reseedFrom(zeroReader{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 300; i++ {
+		if r.Uint32() != ref.Uint32() {
+			t.Fatalf("[%v] output differs after the first reseed", i)
+		}
+	}
+
+	if len(events) != 1 || events[0].Words != 300 || events[0].Err != nil {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	r.Uint64()
+	if len(events) != 2 {
+		t.Fatalf("expected second reseed, got %+v", events)
+	}
+}
+
+func TestReseedingInterval(t *testing.T) {
+	var events []ReseedEvent
+	clock := time.Unix(0, 0)
+
+	isa := NewIsaac64()
+	isa.Seed(7)
+	r := NewReseedingIsaac64(isa, ReseedPolicy{
+		Interval: time.Minute,
+		Source:   bytes.NewReader(make([]byte, 2048)),
+		OnReseed: func(e ReseedEvent) { events = append(events, e) },
+	})
+	r.now = func() time.Time { return clock }
+	r.last = clock
+
+	r.Uint64()
+	clock = clock.Add(59 * time.Second)
+	r.Uint64()
+	if len(events) != 0 {
+		t.Fatalf("reseeded too early: %+v", events)
+	}
+
+	clock = clock.Add(time.Second)
+	r.Uint64()
+	if len(events) != 1 || events[0].Elapsed != time.Minute || events[0].Words != 2 || events[0].Err != nil {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	// The source is exhausted now, so the next attempt must report an error.
+	clock = clock.Add(time.Minute)
+	r.Uint64()
+	if len(events) != 2 || events[1].Err == nil {
+		t.Fatalf("expected failed reseed, got %+v", events)
+	}
+	if err := r.Reseed(); err == nil {
+		t.Fatal("expected error from exhausted source")
+	}
+}
+
+func TestReseedingBits(t *testing.T) {
+	var events []ReseedEvent
+	isa := NewIsaac()
+	isa.Seed(7)
+	ref := NewIsaac()
+	ref.Seed(7)
+
+	r := NewReseedingIsaac(isa, ReseedPolicy{
+		Words:    5,
+		Source:   zeroReader{},
+		OnReseed: func(e ReseedEvent) { events = append(events, e) },
+	})
+
+	// One word for the Bools, one for Bits(31), which leaves a bit buffered,
+	// and two for Bits(64).
+	for i := 0; i < 32; i++ {
+		if r.Bool() != ref.Bool() {
+			t.Fatalf("[%v] Bool differs", i)
+		}
+	}
+	if r.Bits(31) != ref.Bits(31) || r.Bits(64) != ref.Bits(64) {
+		t.Fatal("Bits differs")
+	}
+
+	// The buffered bit takes no word, the next Bool the fifth.
+	r.Bits(1)
+	r.Bool()
+	if len(events) != 0 {
+		t.Fatalf("reseeded too early: %+v", events)
+	}
+	r.Bool()
+	if len(events) != 1 || events[0].Words != 5 {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	if err := ref.reseedFrom(zeroReader{}); err != nil {
+		t.Fatal(err)
+	}
+	ref.Bool()
+	for i := 0; i < 10; i++ {
+		if r.Bits(7) != ref.Bits(7) {
+			t.Fatalf("[%v] Bits differs after reseed", i)
+		}
+	}
+}
+
+func TestReseedingErr(t *testing.T) {
+	source := bytes.NewReader(nil)
+	isa := NewIsaac64()
+	isa.Seed(7)
+	r := NewReseedingIsaac64(isa, ReseedPolicy{Words: 1, Source: source})
+
+	r.Uint64()
+	r.Uint64()
+	if r.Err() == nil {
+		t.Fatal("failed reseed not reported")
+	}
+
+	source.Reset(make([]byte, 2048))
+	if err := r.Reseed(); err != nil || r.Err() != nil {
+		t.Fatalf("Reseed %v, Err %v after successful reseed", err, r.Err())
+	}
+}
@@ -0,0 +1,378 @@
+package isaac
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errCheckpoints = errors.New("isaac: invalid checkpoints encoding")
+
+// Checkpoints stores snapshots of the state of a generator taken every
+// Interval blocks, starting with its state at position 0. It is shared by a
+// seekable generator and grows as the generator visits new blocks, so it can
+// be persisted with MarshalBinary and used later to restore the generator
+// without replaying the stream.
+//
+// Each snapshot holds two blocks of words, so the memory used after visiting
+// k blocks is about 2k/Interval blocks, while reaching any position costs at
+// most Interval blocks of generation.
+type Checkpoints struct {
+	interval uint64
+	snaps    [][]byte
+}
+
+// Interval returns the number of blocks between two snapshots.
+func (cp *Checkpoints) Interval() int {
+	return int(cp.interval)
+}
+
+// Len returns the number of snapshots taken so far.
+func (cp *Checkpoints) Len() int {
+	return len(cp.snaps)
+}
+
+// MarshalBinary encodes the interval and all snapshots.
+func (cp *Checkpoints) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 2*binary.MaxVarintLen64)
+	b = appendUvarint(b, cp.interval)
+	b = appendUvarint(b, uint64(len(cp.snaps)))
+	for _, s := range cp.snaps {
+		b = appendUvarint(b, uint64(len(s)))
+		b = append(b, s...)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary restores checkpoints encoded by MarshalBinary.
+func (cp *Checkpoints) UnmarshalBinary(data []byte) error {
+	interval, n := binary.Uvarint(data)
+	if n <= 0 || interval == 0 {
+		return errCheckpoints
+	}
+	data = data[n:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count == 0 || count > uint64(len(data)) {
+		return errCheckpoints
+	}
+	data = data[n:]
+
+	snaps := make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return errCheckpoints
+		}
+
+		s := append([]byte(nil), data[n:n+int(size)]...)
+		if len(s) < 2 || (s[0] != stateKind32 && s[0] != stateKind64) {
+			return errCheckpoints
+		}
+		if _, ok := checkState(s, s[0], int(s[0]/8)); !ok {
+			return errCheckpoints
+		}
+		if i > 0 && (s[0] != snaps[0][0] || len(s) != len(snaps[0])) {
+			return errCheckpoints
+		}
+
+		snaps = append(snaps, s)
+		data = data[n+int(size):]
+	}
+	if len(data) != 0 {
+		return errCheckpoints
+	}
+
+	cp.interval, cp.snaps = interval, snaps
+	return nil
+}
+
+type checkpointable interface {
+	seeded() bool
+	refill()
+	remaining() uint64
+	setRemaining(c uint64)
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary(data []byte) error
+}
+
+// seeker maps positions of a stream to blocks. Block 0 is the one holding
+// position 0 and has first words left in it, all following blocks have n.
+type seeker struct {
+	cp    *Checkpoints
+	n     uint64
+	first uint64
+	block uint64
+}
+
+// unknownBlock marks a seeker whose generator is not known to be at any
+// block of the stream.
+const unknownBlock = ^uint64(0)
+
+func newSeeker(ctx checkpointable, n uint64, interval int) seeker {
+	if interval < 1 {
+		panic("isaac: checkpoint interval must be positive")
+	}
+	if !ctx.seeded() {
+		panic("isaac: seekable generator needs a seeded instance")
+	}
+
+	s := seeker{cp: &Checkpoints{interval: uint64(interval)}, n: n, first: ctx.remaining()}
+	s.record(ctx)
+	return s
+}
+
+func restoreSeeker(ctx checkpointable, cp *Checkpoints) (seeker, error) {
+	if len(cp.snaps) == 0 {
+		return seeker{}, errCheckpoints
+	}
+	if err := ctx.UnmarshalBinary(cp.snaps[0]); err != nil {
+		return seeker{}, err
+	}
+
+	n := uint64(1) << cp.snaps[0][1]
+	return seeker{cp: cp, n: n, first: ctx.remaining()}, nil
+}
+
+// next makes sure that a word is left in the current block, moving to the
+// next block if needed.
+func (s *seeker) next(ctx checkpointable) {
+	if ctx.remaining() == 0 {
+		s.advance(ctx)
+	}
+}
+
+func (s *seeker) advance(ctx checkpointable) {
+	ctx.refill()
+	s.block++
+	s.record(ctx)
+}
+
+func (s *seeker) record(ctx checkpointable) {
+	cp := s.cp
+	if s.block%cp.interval == 0 && s.block/cp.interval == uint64(len(cp.snaps)) {
+		b, err := ctx.MarshalBinary()
+		if err != nil {
+			panic(err)
+		}
+		cp.snaps = append(cp.snaps, b)
+	}
+}
+
+func (s *seeker) pos(ctx checkpointable) uint64 {
+	if s.block == 0 {
+		return s.first - ctx.remaining()
+	}
+
+	return s.first + (s.block-1)*s.n + s.n - ctx.remaining()
+}
+
+func (s *seeker) seek(ctx checkpointable, pos uint64) {
+	block, left := uint64(0), s.first-pos
+	if pos >= s.first {
+		q := pos - s.first
+		block, left = q/s.n+1, s.n-q%s.n
+	}
+
+	if block != s.block {
+		j := block / s.cp.interval
+		if j >= uint64(len(s.cp.snaps)) {
+			j = uint64(len(s.cp.snaps)) - 1
+		}
+
+		// Generating forward from the current block is never slower than
+		// restoring the closest snapshot.
+		if s.block == unknownBlock || s.block < j*s.cp.interval || s.block > block {
+			if err := ctx.UnmarshalBinary(s.cp.snaps[j]); err != nil {
+				panic(err)
+			}
+			s.block = j * s.cp.interval
+		}
+		for s.block < block {
+			s.advance(ctx)
+		}
+	}
+
+	ctx.setRemaining(left)
+}
+
+// seeded reports whether the state of ctx was initialized, with a seed or
+// by InitUnseeded: initialization never leaves the memory all zeros.
+func (ctx *Isaac) seeded() bool {
+	if ctx.wiped {
+		panic(errWiped)
+	}
+
+	for _, v := range ctx.mem() {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *Isaac) remaining() uint64 {
+	return uint64(ctx.randcnt)
+}
+
+func (ctx *Isaac) setRemaining(c uint64) {
+	ctx.randcnt = uint32(c)
+}
+
+// seeded reports whether the state of ctx was initialized, with a seed or
+// by InitUnseeded: initialization never leaves the memory all zeros.
+func (ctx *Isaac64) seeded() bool {
+	if ctx.wiped {
+		panic(errWiped)
+	}
+
+	for _, v := range ctx.mem() {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *Isaac64) remaining() uint64 {
+	return ctx.randcnt
+}
+
+func (ctx *Isaac64) setRemaining(c uint64) {
+	ctx.randcnt = c
+}
+
+// SeekableIsaac wraps ISAAC instance and records Checkpoints of its state, so
+// that any position of its stream can be reached without generating it from
+// the start. Positions count the values returned by Uint32, starting at 0
+// with the state the wrapper was created with.
+type SeekableIsaac struct {
+	ctx     *Isaac
+	scratch *Isaac
+	seeker
+	at seeker
+}
+
+// NewSeekableIsaac returns a new seekable wrapper around ctx, which must
+// already be seeded and not be used directly afterwards. A snapshot is taken
+// every interval blocks. It panics if ctx is not seeded or if interval is
+// less than 1.
+func NewSeekableIsaac(ctx *Isaac, interval int) *SeekableIsaac {
+	return &SeekableIsaac{ctx: ctx, seeker: newSeeker(ctx, uint64(1)<<ctx.sizeLog2(), interval)}
+}
+
+// RestoreSeekableIsaac returns a new seekable ISAAC instance at position 0
+// of the stream recorded in cp, such as one decoded by
+// Checkpoints.UnmarshalBinary.
+func RestoreSeekableIsaac(cp *Checkpoints) (*SeekableIsaac, error) {
+	ctx := NewIsaac()
+	s, err := restoreSeeker(ctx, cp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SeekableIsaac{ctx: ctx, seeker: s}, nil
+}
+
+// Checkpoints returns the snapshots recorded so far.
+func (s *SeekableIsaac) Checkpoints() *Checkpoints {
+	return s.cp
+}
+
+// Uint32 returns the value at the current position and moves to the next.
+func (s *SeekableIsaac) Uint32() uint32 {
+	s.next(s.ctx)
+	return s.ctx.next()
+}
+
+// Pos returns the current position.
+func (s *SeekableIsaac) Pos() uint64 {
+	return s.pos(s.ctx)
+}
+
+// Seek moves to the given position, forwards or backwards.
+func (s *SeekableIsaac) Seek(pos uint64) {
+	s.seek(s.ctx, pos)
+}
+
+// At returns the value at position i without moving the current position.
+func (s *SeekableIsaac) At(i uint64) uint32 {
+	if s.scratch == nil {
+		s.scratch = NewIsaac()
+		s.at = s.seeker
+		s.at.block = unknownBlock
+	}
+
+	s.at.seek(s.scratch, i)
+	return s.scratch.next()
+}
+
+// SeekableIsaac64 wraps ISAAC64 instance and records Checkpoints of its
+// state, so that any position of its stream can be reached without
+// generating it from the start. Positions count the values returned by
+// Uint64, starting at 0 with the state the wrapper was created with.
+type SeekableIsaac64 struct {
+	ctx     *Isaac64
+	scratch *Isaac64
+	seeker
+	at seeker
+}
+
+// NewSeekableIsaac64 returns a new seekable wrapper around ctx, which must
+// already be seeded and not be used directly afterwards. A snapshot is taken
+// every interval blocks. It panics if ctx is not seeded or if interval is
+// less than 1.
+func NewSeekableIsaac64(ctx *Isaac64, interval int) *SeekableIsaac64 {
+	return &SeekableIsaac64{ctx: ctx, seeker: newSeeker(ctx, uint64(1)<<ctx.sizeLog2(), interval)}
+}
+
+// RestoreSeekableIsaac64 returns a new seekable ISAAC64 instance at position
+// 0 of the stream recorded in cp, such as one decoded by
+// Checkpoints.UnmarshalBinary.
+func RestoreSeekableIsaac64(cp *Checkpoints) (*SeekableIsaac64, error) {
+	ctx := NewIsaac64()
+	s, err := restoreSeeker(ctx, cp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SeekableIsaac64{ctx: ctx, seeker: s}, nil
+}
+
+// Checkpoints returns the snapshots recorded so far.
+func (s *SeekableIsaac64) Checkpoints() *Checkpoints {
+	return s.cp
+}
+
+// Uint64 returns the value at the current position and moves to the next.
+func (s *SeekableIsaac64) Uint64() uint64 {
+	s.next(s.ctx)
+	return s.ctx.next()
+}
+
+// Pos returns the current position.
+func (s *SeekableIsaac64) Pos() uint64 {
+	return s.pos(s.ctx)
+}
+
+// Seek moves to the given position, forwards or backwards.
+func (s *SeekableIsaac64) Seek(pos uint64) {
+	s.seek(s.ctx, pos)
+}
+
+// At returns the value at position i without moving the current position.
+func (s *SeekableIsaac64) At(i uint64) uint64 {
+	if s.scratch == nil {
+		s.scratch = NewIsaac64()
+		s.at = s.seeker
+		s.at.block = unknownBlock
+	}
+
+	s.at.seek(s.scratch, i)
+	return s.scratch.next()
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
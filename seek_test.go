@@ -0,0 +1,153 @@
+package isaac
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSeekable(t *testing.T) {
+	for _, log2 := range []int{4, 8} {
+		ref := NewIsaacSize(log2)
+		ref.SeedString("seek")
+		ctx := NewIsaacSize(log2)
+		ctx.SeedString("seek")
+
+		// Position 0 may be in the middle of a block.
+		for i := 0; i < 5; i++ {
+			ref.Uint32()
+			ctx.Uint32()
+		}
+		words := make([]uint32, 40<<log2)
+		for i := range words {
+			words[i] = ref.Uint32()
+		}
+
+		s := NewSeekableIsaac(ctx, 3)
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 200; i++ {
+			pos := uint64(r.Intn(len(words) - 10))
+			s.Seek(pos)
+			for j := 0; j < 10; j++ {
+				if n, v := s.Uint32(), words[pos+uint64(j)]; n != v {
+					t.Fatalf("2^%v at %v: %x expected but found %x", log2, pos+uint64(j), v, n)
+				}
+			}
+
+			at := uint64(r.Intn(len(words)))
+			if n, v := s.At(at), words[at]; n != v {
+				t.Fatalf("2^%v At(%v): %x expected but found %x", log2, at, v, n)
+			}
+			if s.Pos() != pos+10 {
+				t.Fatalf("2^%v: position %v expected but found %v", log2, pos+10, s.Pos())
+			}
+		}
+	}
+}
+
+func TestSeekable64(t *testing.T) {
+	ref := NewIsaac64()
+	ref.SeedString("seek")
+	words := make([]uint64, 20*256)
+	for i := range words {
+		words[i] = ref.Uint64()
+	}
+
+	ctx := NewIsaac64()
+	ctx.SeedString("seek")
+	s := NewSeekableIsaac64(ctx, 4)
+
+	// Linear generation through the wrapper records every snapshot.
+	for i, v := range words {
+		if n := s.Uint64(); n != v {
+			t.Fatalf("[%v] %x expected but found %x", i, v, n)
+		}
+	}
+	if n := s.Checkpoints().Len(); n != 5 {
+		t.Fatalf("5 snapshots expected but found %v", n)
+	}
+
+	last := uint64(len(words) - 1)
+	for _, pos := range []uint64{last, 0, 1000, 999, 255, 256, 3000} {
+		s.Seek(pos)
+		if n := s.Uint64(); n != words[pos] {
+			t.Fatalf("at %v: %x expected but found %x", pos, words[pos], n)
+		}
+		if n := s.At(last - pos); n != words[last-pos] {
+			t.Fatalf("At(%v): %x expected but found %x", last-pos, words[last-pos], n)
+		}
+	}
+}
+
+func TestCheckpointsPersist(t *testing.T) {
+	ref := NewIsaac()
+	ref.Seed(7)
+	words := make([]uint32, 10*256)
+	for i := range words {
+		words[i] = ref.Uint32()
+	}
+
+	ctx := NewIsaac()
+	ctx.Seed(7)
+	s := NewSeekableIsaac(ctx, 2)
+	s.Seek(uint64(len(words) - 1))
+
+	data, err := s.Checkpoints().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cp Checkpoints
+	if err := cp.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if cp.Interval() != 2 || cp.Len() != s.Checkpoints().Len() {
+		t.Fatalf("checkpoints differ after decoding: %v/%v", cp.Interval(), cp.Len())
+	}
+
+	restored, err := RestoreSeekableIsaac(&cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pos := range []uint64{2000, 0, 700} {
+		if n := restored.At(pos); n != words[pos] {
+			t.Fatalf("At(%v): %x expected but found %x", pos, words[pos], n)
+		}
+	}
+	if n := restored.Uint32(); n != words[0] {
+		t.Fatalf("%x expected but found %x", words[0], n)
+	}
+
+	if _, err := RestoreSeekableIsaac64(&cp); err != errStateEncoding {
+		t.Fatalf("expected %v, got %v", errStateEncoding, err)
+	}
+	if err := cp.UnmarshalBinary(data[:len(data)-1]); err != errCheckpoints {
+		t.Fatalf("expected %v, got %v", errCheckpoints, err)
+	}
+}
+
+func TestSeekableUnseeded(t *testing.T) {
+	wiped := NewIsaac()
+	wiped.Seed(1)
+	wiped.Wipe()
+	expectWiped(t, "NewSeekableIsaac", func() { NewSeekableIsaac(wiped, 1) })
+
+	for name, f := range map[string]func(){
+		"zero value":     func() { NewSeekableIsaac(&Isaac{}, 1) },
+		"NewIsaac":       func() { NewSeekableIsaac(NewIsaac(), 1) },
+		"NewIsaacSize":   func() { NewSeekableIsaac(NewIsaacSize(MinSizeLog2), 1) },
+		"zero value 64":  func() { NewSeekableIsaac64(&Isaac64{}, 1) },
+		"NewIsaac64Size": func() { NewSeekableIsaac64(NewIsaac64Size(MinSizeLog2), 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expected panic for an unseeded generator", name)
+				}
+			}()
+			f()
+		}()
+	}
+
+	// InitUnseeded initializes the state without a seed.
+	NewSeekableIsaac(NewIsaacUnseeded(), 1)
+	NewSeekableIsaac64(NewIsaac64Unseeded(), 1)
+}
@@ -0,0 +1,211 @@
+package isaac
+
+import "testing"
+
+// Outputs of rand.c and isaac64.c built with RANDSIZL changed, after seeding
+// randrsl[0] = 1 and randinit(TRUE): the first outputs, and later the eight
+// outputs from position 2.5 * 2^RANDSIZL on, in the third generated block.
+var sizeVectors = []struct {
+	log2    int
+	v32     []uint32
+	v64     []uint64
+	later32 []uint32
+	later64 []uint64
+}{
+	{
+		log2: 3,
+		v32: []uint32{
+			0xf59b8b38, 0xd27aedd9, 0xbb70bab5, 0x17b33a3f, 0x9af9c06a, 0x695d3251, 0x72fbedce, 0xee7a318f,
+		},
+		v64: []uint64{
+			0x45958732873991cf, 0xf959bd9a8c59fd29, 0x0add68cb07b96539, 0xc71931195e1aed72,
+			0x5507a06913df35f8, 0x45acb71eb5500573, 0x9f3e3ff63e7123b6, 0x3554b70acf910221,
+		},
+		later32: []uint32{
+			0xe39cd821, 0x6fb68d48, 0x84e0f3d5, 0x0ff2b76a, 0x0060f5ae, 0xbc154c4e, 0xcf6c3d63, 0xe924887d,
+		},
+		later64: []uint64{
+			0xaf04e6909a520868, 0x99de7e342c6f119a, 0xe3985600d6fac1e9, 0x4cba875497c0ce06,
+			0xc02091a9f9e5c94a, 0x8467b55dcbbf5181, 0x981d93c4fcf8db4c, 0x6bf880e096ef3d79,
+		},
+	},
+	{
+		log2: 4,
+		v32: []uint32{
+			0x462b8be5, 0x48a424dd, 0x32b971c2, 0x569e78ef, 0xb5836efb, 0x0f602817, 0xc21c8e3f, 0xc9b3931a,
+			0xac956108, 0x763a3a68, 0x299061ac, 0x5bcae81d, 0xe86732ba, 0x91cfba77, 0xe94dbfd2, 0x6a774fd2,
+			0x15bed245, 0x61e42c68, 0x779dff99, 0xe5dec68d, 0xfbe3d045, 0x1e8d3928, 0x702c5568, 0x56c7aba1,
+			0x3b5e71be, 0x169f7959, 0x8e04b6f3, 0x71d8c292, 0x57086621, 0x25436f9c, 0x982937ab, 0xd5b161cb,
+			0xf0023549, 0xb747b22a, 0xf78876bd, 0x29e1b936, 0x120af7a0, 0x4ef11026, 0x0fc27252, 0xc8479860,
+		},
+		v64: []uint64{
+			0xb1a5cec479ac6f00, 0x7998d077f7fb04d7, 0x4d16671ef85290e5, 0x081198f78cb4e277,
+			0xa8ad45d798ee4ffb, 0xc3c0b05616414a7c, 0x44e961ab2d26fb4b, 0x804b5c6e4e27b7b1,
+			0xc06a87992df4f786, 0x1d7dec792171b21c, 0xf8038cecf373a96a, 0x1b9e757094934a40,
+			0x733355d1928f2774, 0xa3ec6e515a259710, 0xb7cec4ae5076bef2, 0xe06732e4eaee4e23,
+			0x28f4a95306ed8329, 0xbaf44dcb203fe2bd, 0x35813074408e9273, 0xc1d6800273d47c25,
+			0x3f04abaa36d158b8, 0xa9ad6cd4c9a6618b, 0x8df0d442d6c2febe, 0x012c62b2e1c1519c,
+			0x77cef64c4a70643e, 0x2b448431550b5487, 0x70767da1db0e6550, 0x55e54486fb13191f,
+			0xd0f25d6dee2b22c4, 0x4febfdc0e61de74d, 0x989c7cd49cbba24c, 0xfd072c6fd29a26e7,
+			0x4e298c105be01591, 0x41fac28490475773, 0x4801aa5d608f959e, 0xf8b2f1d6290ec630,
+			0x3e56532993b2eeff, 0xcefc1c59651d41b4, 0x7b6023b5745cf2af, 0x32a3c80781e1cb78,
+		},
+		later32: []uint32{
+			0xf84a017f, 0x2570675e, 0xd606b167, 0x32e58699, 0xc6f9bb19, 0xdcd5c221, 0x5c9981ce, 0x5880069a,
+		},
+		later64: []uint64{
+			0x4c3c30518fd85f40, 0x69bd22c4eedec0a7, 0x504fbbaed628cb8b, 0xf37ef398f65cb757,
+			0x0b707255a87d32fe, 0x01efd90fb82fa24b, 0x4b51215ff3879340, 0x1a135c596efa3002,
+		},
+	},
+	{
+		log2: 10,
+		v32: []uint32{
+			0x97144bc9, 0x67704f02, 0x8049cf80, 0x8fa21ea4, 0x3065ada8, 0x845dc46e, 0x2a7e3447, 0x3def4ec7,
+			0x050cbab4, 0x1e00536c, 0x30b95682, 0x041c9c61, 0x0da54a10, 0xa69ab199, 0x36ce080f, 0x36d06b7d,
+		},
+		v64: []uint64{
+			0x616ad84087769be9, 0x7559733df2e7bf75, 0x07bb76784f6f6c4e, 0x9cee700e4aa98dd4,
+			0x631ea28e5bd4dc0a, 0x26e6ef02e7e9bffd, 0xf277d54311d09605, 0x6fafca6f3ef1cc4d,
+		},
+		later32: []uint32{
+			0xaa10ffc3, 0x2fb5da5f, 0x067a7714, 0x308304c8, 0x6de70ff5, 0xd26b3ccd, 0x593722a4, 0xd506c7ee,
+		},
+		later64: []uint64{
+			0xd4b8f0702022c72f, 0x36614657f740e71f, 0x4e263228900b102b, 0xcf0205838437c96f,
+			0x7fc6db15475d1591, 0x797fefecaca09bc5, 0x310c17a6c447190e, 0x7376079df2d70010,
+		},
+	},
+	{
+		log2: 16,
+		v32: []uint32{
+			0x86bc80ac, 0x522ca925, 0x8126d8ca, 0xd53fdb38, 0x72332767, 0xcb0da7ee, 0x5e509bdf, 0x64721ab7,
+		},
+		v64: []uint64{
+			0xf24bfb126570bccd, 0x0b041d049d48fd71, 0xc58056e1f189e52a, 0x1b7b615ce0bfa849,
+			0x4e45605a84b8c82b, 0xd88a6fde7fadd1ed, 0xb6eb9fa175999062, 0x2b8fe1ca4c7c3f33,
+		},
+		later32: []uint32{
+			0xdfd594e9, 0x1700cb15, 0x294cf240, 0x076cb9d3, 0xfa2a7fb6, 0xea372ace, 0x00b0cd76, 0x033614b2,
+		},
+		later64: []uint64{
+			0x19454a1fc28437a6, 0xc56fd2ebfebf25bf, 0x5c4a742ac72e146a, 0x3c3c1a9c06c78218,
+			0x332f4d6f5f47f39e, 0xf3977cf11b03d834, 0x90fe913c7794eef5, 0x600ac0bc7940bff5,
+		},
+	},
+}
+
+func TestSize(t *testing.T) {
+	for _, sv := range sizeVectors {
+		later := 5 << sv.log2 / 2
+
+		isa := NewIsaacSize(sv.log2)
+		isa.Seed(1)
+		for i, v := range sv.v32 {
+			if n := isa.Uint32(); n != v {
+				t.Fatalf("2^%v [%v] %x expected but found %x", sv.log2, i, v, n)
+			}
+		}
+		for i := len(sv.v32); i < later; i++ {
+			isa.Uint32()
+		}
+		for i, v := range sv.later32 {
+			if n := isa.Uint32(); n != v {
+				t.Fatalf("2^%v [%v] %x expected but found %x", sv.log2, later+i, v, n)
+			}
+		}
+
+		isa64 := NewIsaac64Size(sv.log2)
+		isa64.Seed(1)
+		for i, v := range sv.v64 {
+			if n := isa64.Uint64(); n != v {
+				t.Fatalf("2^%v [%v] %x expected but found %x", sv.log2, i, v, n)
+			}
+		}
+		for i := len(sv.v64); i < later; i++ {
+			isa64.Uint64()
+		}
+		for i, v := range sv.later64 {
+			if n := isa64.Uint64(); n != v {
+				t.Fatalf("2^%v [%v] %x expected but found %x", sv.log2, later+i, v, n)
+			}
+		}
+	}
+}
+
+// TestSizedMatchesFixed checks the generic loop against the one with constant
+// masks, which the default size uses.
+func TestSizedMatchesFixed(t *testing.T) {
+	isa := NewIsaac()
+	isa.SeedString("sized")
+	mm := isa.randmem
+	var r [256]uint32
+	a, b, c := isa.aa, isa.bb, isa.cc
+	for i := 0; i < 64; i++ {
+		isa.isaac()
+		c++
+		a, b = isaacSized(mm[:], r[:], defaultSizeLog2, a, b+c)
+		if r != isa.randrsl || mm != isa.randmem {
+			t.Fatalf("block %v differs", i)
+		}
+	}
+
+	isa64 := NewIsaac64()
+	isa64.SeedString("sized")
+	mm64 := isa64.randmem
+	var r64 [256]uint64
+	a64, b64, c64 := isa64.aa, isa64.bb, isa64.cc
+	for i := 0; i < 64; i++ {
+		isa64.isaac64()
+		c64++
+		a64, b64 = isaac64Sized(mm64[:], r64[:], defaultSizeLog2, a64, b64+c64)
+		if r64 != isa64.randrsl || mm64 != isa64.randmem {
+			t.Fatalf("block %v differs", i)
+		}
+	}
+}
+
+func TestSizeRange(t *testing.T) {
+	for _, log2 := range []int{MinSizeLog2 - 1, MaxSizeLog2 + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("2^%v: expected panic", log2)
+				}
+			}()
+			NewIsaacSize(log2)
+		}()
+	}
+
+	for _, log2 := range []int{MinSizeLog2, MaxSizeLog2} {
+		isa := NewIsaacSize(log2)
+		isa.SeedString("range")
+		for i := 0; i < 3<<log2; i++ {
+			isa.Uint64()
+		}
+
+		isa64 := NewIsaac64Size(log2)
+		isa64.SeedString("range")
+		for i := 0; i < 3<<log2; i++ {
+			isa64.Uint64()
+		}
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var isa Isaac
+	isa.Seed(1)
+	ref := NewIsaac()
+	ref.Seed(1)
+	if isa.Uint64() != ref.Uint64() {
+		t.Fatal("zero value differs from NewIsaac")
+	}
+
+	var isa64 Isaac64
+	isa64.Seed(1)
+	ref64 := NewIsaac64()
+	ref64.Seed(1)
+	if isa64.Uint64() != ref64.Uint64() {
+		t.Fatal("zero value differs from NewIsaac64")
+	}
+}
@@ -0,0 +1,164 @@
+package isaac
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The encoding of a state starts with the kind of generator and the log2 of
+// its size, followed by randcnt, aa, bb, cc and the number of bytes absorbed
+// by Write since the last mixing, and then randrsl and randmem, all
+// little-endian in the word size of the generator.
+const (
+	stateKind32 = 32
+	stateKind64 = 64
+
+	// stateHeader is the number of words before randrsl.
+	stateHeader = 5
+)
+
+var errStateEncoding = errors.New("isaac: invalid state encoding")
+
+// MarshalBinary encodes the current state of ISAAC instance, including the
+// position within the current block. Settings such as SetInitValues,
+// SetUint64Compat and health tests are not part of the state, and neither
+// are bits buffered by Bool.
+func (ctx *Isaac) MarshalBinary() ([]byte, error) {
+	if ctx.wiped {
+		return nil, errWiped
+	}
+	r, m := ctx.rsl(), ctx.mem()
+	n := len(r)
+	b := make([]byte, 2, 2+4*(stateHeader+2*n))
+	b[0], b[1] = stateKind32, byte(ctx.sizeLog2())
+	for _, v := range []uint32{ctx.randcnt, ctx.aa, ctx.bb, ctx.cc, ctx.absorbed} {
+		b = appendUint32(b, v)
+	}
+	for _, v := range r {
+		b = appendUint32(b, v)
+	}
+	for _, v := range m {
+		b = appendUint32(b, v)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary. The size of the
+// state is taken from the encoding.
+func (ctx *Isaac) UnmarshalBinary(data []byte) error {
+	if ctx.wiped {
+		return errWiped
+	}
+
+	sizl, ok := checkState(data, stateKind32, 4)
+	if !ok {
+		return errStateEncoding
+	}
+	if ctx.sizeLog2() != sizl {
+		ctx.alloc(sizl)
+	}
+
+	r, m := ctx.rsl(), ctx.mem()
+	n := len(r)
+	data = data[2:]
+	word := func(i int) uint32 { return binary.LittleEndian.Uint32(data[4*i:]) }
+	ctx.randcnt, ctx.aa, ctx.bb, ctx.cc, ctx.absorbed = word(0), word(1), word(2), word(3), word(4)
+	for i := 0; i < n; i++ {
+		r[i] = word(stateHeader + i)
+		m[i] = word(stateHeader + n + i)
+	}
+	ctx.resv = bitReservoir{}
+
+	return nil
+}
+
+// MarshalBinary encodes the current state of ISAAC64 instance, including the
+// position within the current block. Settings such as SetInitValues,
+// SetHalfWords and health tests are not part of the state, and neither are
+// a buffered half word and bits buffered by Bool.
+func (ctx *Isaac64) MarshalBinary() ([]byte, error) {
+	if ctx.wiped {
+		return nil, errWiped
+	}
+	r, m := ctx.rsl(), ctx.mem()
+	n := len(r)
+	b := make([]byte, 2, 2+8*(stateHeader+2*n))
+	b[0], b[1] = stateKind64, byte(ctx.sizeLog2())
+	for _, v := range []uint64{ctx.randcnt, ctx.aa, ctx.bb, ctx.cc, ctx.absorbed} {
+		b = appendUint64(b, v)
+	}
+	for _, v := range r {
+		b = appendUint64(b, v)
+	}
+	for _, v := range m {
+		b = appendUint64(b, v)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary. The size of the
+// state is taken from the encoding.
+func (ctx *Isaac64) UnmarshalBinary(data []byte) error {
+	if ctx.wiped {
+		return errWiped
+	}
+
+	sizl, ok := checkState(data, stateKind64, 8)
+	if !ok {
+		return errStateEncoding
+	}
+	if ctx.sizeLog2() != sizl {
+		ctx.alloc(sizl)
+	}
+
+	r, m := ctx.rsl(), ctx.mem()
+	n := len(r)
+	data = data[2:]
+	word := func(i int) uint64 { return binary.LittleEndian.Uint64(data[8*i:]) }
+	ctx.randcnt, ctx.aa, ctx.bb, ctx.cc, ctx.absorbed = word(0), word(1), word(2), word(3), word(4)
+	for i := 0; i < n; i++ {
+		r[i] = word(stateHeader + i)
+		m[i] = word(stateHeader + n + i)
+	}
+	ctx.half, ctx.hasHalf = 0, false
+	ctx.resv = bitReservoir{}
+
+	return nil
+}
+
+// checkState validates the header and length of an encoded state and returns
+// the log2 of its size.
+func checkState(data []byte, kind byte, size int) (uint, bool) {
+	if len(data) < 2 || data[0] != kind {
+		return 0, false
+	}
+
+	sizl := int(data[1])
+	if sizl < MinSizeLog2 || sizl > MaxSizeLog2 || len(data) != 2+size*(stateHeader+2<<sizl) {
+		return 0, false
+	}
+
+	// randcnt never exceeds the block size, and absorbed stays below the
+	// size of a seed.
+	word := func(i int) uint64 {
+		if size == 4 {
+			return uint64(binary.LittleEndian.Uint32(data[2+4*i:]))
+		}
+		return binary.LittleEndian.Uint64(data[2+8*i:])
+	}
+	if word(0) > 1<<sizl || word(4) >= uint64(size)<<sizl {
+		return 0, false
+	}
+
+	return uint(sizl), true
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
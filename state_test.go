@@ -0,0 +1,131 @@
+package isaac
+
+import "testing"
+
+func TestMarshalBinary(t *testing.T) {
+	isa := NewIsaacSize(4)
+	isa.Seed(1)
+	isa.Uint32()
+	data, err := isa.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := NewIsaac()
+	if err := other.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if x, y := isa.Uint32(), other.Uint32(); x != y {
+			t.Fatalf("[%v] %x != %x", i, x, y)
+		}
+	}
+
+	isa64 := NewIsaac64()
+	isa64.Seed(1)
+	isa64.Uint64()
+	data64, err := isa64.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other64 := NewIsaac64Size(4)
+	if err := other64.UnmarshalBinary(data64); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 600; i++ {
+		if x, y := isa64.Uint64(), other64.Uint64(); x != y {
+			t.Fatalf("[%v] %x != %x", i, x, y)
+		}
+	}
+
+	if err := other.UnmarshalBinary(data64); err != errStateEncoding {
+		t.Fatalf("ISAAC64 state: expected %v, got %v", errStateEncoding, err)
+	}
+	if err := other64.UnmarshalBinary(data64[:len(data64)-1]); err != errStateEncoding {
+		t.Fatalf("truncated state: expected %v, got %v", errStateEncoding, err)
+	}
+	data64[3] = 0xff
+	if err := other64.UnmarshalBinary(data64); err != errStateEncoding {
+		t.Fatalf("invalid randcnt: expected %v, got %v", errStateEncoding, err)
+	}
+
+	isa.Wipe()
+	if _, err := isa.MarshalBinary(); err != errWiped {
+		t.Fatalf("expected %v, got %v", errWiped, err)
+	}
+}
+
+func TestClone(t *testing.T) {
+	isa := NewIsaacSize(4)
+	isa.SeedString("clone")
+	isa.EnableHealthTests(HealthConfig{})
+	for i := 0; i < 21; i++ {
+		isa.Uint32()
+	}
+
+	c := isa.Clone()
+	want := make([]uint32, 100)
+	for i := range want {
+		want[i] = isa.Uint32()
+	}
+	for i, v := range want {
+		if n := c.Uint32(); n != v {
+			t.Fatalf("[%v] clone %x, original %x", i, n, v)
+		}
+	}
+	if c.health == isa.health {
+		t.Fatal("clone shares the health monitor")
+	}
+
+	isa64 := NewIsaac64()
+	isa64.SeedString("clone")
+	isa64.Uint64()
+	c64 := isa64.Clone()
+	want64 := make([]uint64, 600)
+	for i := range want64 {
+		want64[i] = isa64.Uint64()
+	}
+	for i, v := range want64 {
+		if n := c64.Uint64(); n != v {
+			t.Fatalf("[%v] clone %x, original %x", i, n, v)
+		}
+	}
+}
+
+// TestCopy checks that a value copy of an instance of the default size is a
+// snapshot, while copies of other sizes share their state as documented.
+func TestCopy(t *testing.T) {
+	isa, ref := NewIsaac(), NewIsaac()
+	isa.Seed(1)
+	ref.Seed(1)
+	snap := *isa
+	for i := 0; i < 300; i++ {
+		isa.Uint32()
+	}
+	if snap != *ref || snap.Uint32() != ref.Uint32() {
+		t.Fatal("value copy changed with the original")
+	}
+
+	isa64, ref64 := NewIsaac64(), NewIsaac64()
+	isa64.Seed(1)
+	ref64.Seed(1)
+	snap64 := *isa64
+	for i := 0; i < 300; i++ {
+		isa64.Uint64()
+	}
+	if snap64 != *ref64 || snap64.Uint64() != ref64.Uint64() {
+		t.Fatal("value copy changed with the original")
+	}
+
+	sized := NewIsaacSize(4)
+	sized.Seed(1)
+	if c := *sized; c.sized != sized.sized {
+		t.Fatal("value copy of a sized instance has its own state")
+	}
+	sized64 := NewIsaac64Size(4)
+	sized64.Seed(1)
+	if c := *sized64; c.sized != sized64.sized {
+		t.Fatal("value copy of a sized instance has its own state")
+	}
+}
@@ -0,0 +1,114 @@
+package stattest
+
+import "math"
+
+const (
+	gammaEps   = 1e-15
+	gammaIters = 1000
+)
+
+// gammaP returns the regularized lower incomplete gamma function P(a, x).
+func gammaP(a, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x < a+1 {
+		return gammaSeries(a, x)
+	}
+
+	return 1 - gammaFraction(a, x)
+}
+
+// gammaQ returns the regularized upper incomplete gamma function Q(a, x).
+func gammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	if x < a+1 {
+		return 1 - gammaSeries(a, x)
+	}
+
+	return gammaFraction(a, x)
+}
+
+func gammaSeries(a, x float64) float64 {
+	lg, _ := math.Lgamma(a)
+	sum := 1 / a
+	del := sum
+	for n := 1; n < gammaIters; n++ {
+		del *= x / (a + float64(n))
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*gammaEps {
+			break
+		}
+	}
+
+	return sum * math.Exp(-x+a*math.Log(x)-lg)
+}
+
+// gammaFraction evaluates Q(a, x) with the modified Lentz's method.
+func gammaFraction(a, x float64) float64 {
+	const tiny = 1e-300
+
+	lg, _ := math.Lgamma(a)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < gammaIters; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < gammaEps {
+			break
+		}
+	}
+
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
+
+// chiSquareP returns the probability of a chi-square statistic at least as
+// large as stat with df degrees of freedom.
+func chiSquareP(stat float64, df int) float64 {
+	return gammaQ(float64(df)/2, stat/2)
+}
+
+// normalP returns the two-sided p-value of a standard normal statistic.
+func normalP(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// poissonP returns the two-sided p-value of observing k events from a
+// Poisson distribution with mean lambda.
+func poissonP(k int, lambda float64) float64 {
+	// P(X <= k) = Q(k+1, lambda) and P(X >= k) = P(k, lambda)
+	lower := gammaQ(float64(k+1), lambda)
+	upper := 1.0
+	if k > 0 {
+		upper = gammaP(float64(k), lambda)
+	}
+
+	return math.Min(1, 2*math.Min(lower, upper))
+}
+
+// chiSquare returns the chi-square statistic of observed counts against
+// expected probabilities for n samples.
+func chiSquare(observed []int, probs []float64, n int) float64 {
+	stat := 0.0
+	for i, o := range observed {
+		e := probs[i] * float64(n)
+		stat += (float64(o) - e) * (float64(o) - e) / e
+	}
+
+	return stat
+}
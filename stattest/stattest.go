@@ -0,0 +1,299 @@
+// Copyright 2021 skdltmxn. All rights reserved.
+//
+// stattest.go
+//
+// Statistical health checks for random generators
+
+// Package stattest runs a fixed battery of statistical tests against a
+// random generator. It is meant to catch gross regressions in the
+// generators of package isaac and is no replacement for full test batteries
+// such as TestU01 or PractRand.
+//
+// All tests consume 64-bit words through Uint64 and read bits from the least
+// significant bit up. Wrap a generator with Uint32Words to test the values of
+// its Uint32 method instead.
+//
+// The package tests run the battery against the generators of package isaac
+// with fixed seeds. Use go test -short for a quick check and
+// go test ./stattest -long for large samples. The -long flag is only defined
+// in this package, so set STATTEST_LONG=1 instead when testing ./... as a
+// whole.
+package stattest
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+// Result is the outcome of a single statistical test.
+type Result struct {
+	// Name identifies the test.
+	Name string
+
+	// Statistic is the raw test statistic.
+	Statistic float64
+
+	// P is the p-value of Statistic under the hypothesis that the
+	// generator is uniformly random.
+	P float64
+}
+
+// Passed reports whether the p-value is not below significance level alpha.
+func (r Result) Passed(alpha float64) bool {
+	return r.P >= alpha
+}
+
+// Test is a statistical test which consumes about n words from g.
+type Test struct {
+	Name string
+	Run  func(g isaac.Generator, n int) Result
+}
+
+// Battery is the fixed set of tests run by Run.
+var Battery = []Test{
+	{"monobit", Monobit},
+	{"runs", Runs},
+	{"chisquare-bytes", ChiSquareBytes},
+	{"serial-correlation", SerialCorrelation},
+	{"birthday-spacings", BirthdaySpacings},
+	{"gap", Gap},
+	{"poker", Poker},
+}
+
+// Run runs every test of Battery against g, each consuming about n words.
+func Run(g isaac.Generator, n int) []Result {
+	results := make([]Result, 0, len(Battery))
+	for _, t := range Battery {
+		results = append(results, t.Run(g, n))
+	}
+
+	return results
+}
+
+// Uint32Words returns a generator whose Uint64 joins two calls of g.Uint32,
+// the first one in the low half, so that the battery tests the 32-bit output
+// of g. The other methods are those of g.
+func Uint32Words(g isaac.Generator) isaac.Generator {
+	return uint32Words{g}
+}
+
+type uint32Words struct {
+	isaac.Generator
+}
+
+func (g uint32Words) Uint64() uint64 {
+	lo := g.Uint32()
+	return uint64(g.Uint32())<<32 | uint64(lo)
+}
+
+// float64s returns a uniform float64 in [0, 1) built from the top 53 bits
+// of a word.
+func float64s(g isaac.Generator) float64 {
+	return float64(g.Uint64()>>11) / (1 << 53)
+}
+
+// Monobit is the frequency test of NIST SP 800-22 over 64n bits.
+func Monobit(g isaac.Generator, n int) Result {
+	ones := 0
+	for i := 0; i < n; i++ {
+		ones += bits.OnesCount64(g.Uint64())
+	}
+
+	total := float64(64 * n)
+	s := (2*float64(ones) - total) / math.Sqrt(total)
+	return Result{Name: "monobit", Statistic: s, P: normalP(s)}
+}
+
+// Runs is the runs test of NIST SP 800-22 over 64n bits.
+func Runs(g isaac.Generator, n int) Result {
+	ones, runs := 0, 1
+	var prev uint64
+	for i := 0; i < n; i++ {
+		x := g.Uint64()
+		ones += bits.OnesCount64(x)
+		runs += bits.OnesCount64((x ^ x>>1) &^ (1 << 63))
+		if i > 0 && prev>>63 != x&1 {
+			runs++
+		}
+		prev = x
+	}
+
+	total := float64(64 * n)
+	pi := float64(ones) / total
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(total) {
+		return Result{Name: "runs", Statistic: float64(runs), P: 0}
+	}
+
+	z := (float64(runs) - 2*total*pi*(1-pi)) / (2 * math.Sqrt(2*total) * pi * (1 - pi))
+	return Result{Name: "runs", Statistic: float64(runs), P: math.Erfc(math.Abs(z))}
+}
+
+// ChiSquareBytes checks the distribution of the 8n bytes of n words.
+func ChiSquareBytes(g isaac.Generator, n int) Result {
+	observed := make([]int, 256)
+	for i := 0; i < n; i++ {
+		x := g.Uint64()
+		for j := 0; j < 8; j++ {
+			observed[byte(x>>(8*j))]++
+		}
+	}
+
+	probs := make([]float64, 256)
+	for i := range probs {
+		probs[i] = 1.0 / 256
+	}
+
+	stat := chiSquare(observed, probs, 8*n)
+	return Result{Name: "chisquare-bytes", Statistic: stat, P: chiSquareP(stat, 255)}
+}
+
+// SerialCorrelation checks the lag-1 correlation of n uniform floats.
+func SerialCorrelation(g isaac.Generator, n int) Result {
+	var sum, sum2, cross float64
+	first := float64s(g)
+	prev := first
+	sum, sum2 = first, first*first
+	for i := 1; i < n; i++ {
+		u := float64s(g)
+		sum += u
+		sum2 += u * u
+		cross += prev * u
+		prev = u
+	}
+	cross += prev * first
+
+	fn := float64(n)
+	r := (fn*cross - sum*sum) / (fn*sum2 - sum*sum)
+	// Under the null hypothesis r has mean -1/(n-1) and variance about 1/n.
+	z := (r + 1/(fn-1)) * math.Sqrt(fn)
+	return Result{Name: "serial-correlation", Statistic: r, P: normalP(z)}
+}
+
+// BirthdaySpacings is Marsaglia's birthday spacings test with 512 birthdays
+// in a year of 2^24 days, repeated n/512 times.
+func BirthdaySpacings(g isaac.Generator, n int) Result {
+	const (
+		m    = 512
+		year = 1 << 24
+	)
+
+	reps := n / m
+	if reps < 1 {
+		reps = 1
+	}
+
+	dups := 0
+	days := make([]uint64, m)
+	spacings := make([]uint64, m)
+	for r := 0; r < reps; r++ {
+		for i := range days {
+			days[i] = g.Uint64() >> 40
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+		spacings[0] = days[0]
+		for i := 1; i < m; i++ {
+			spacings[i] = days[i] - days[i-1]
+		}
+		sort.Slice(spacings, func(i, j int) bool { return spacings[i] < spacings[j] })
+
+		for i := 1; i < m; i++ {
+			if spacings[i] == spacings[i-1] {
+				dups++
+			}
+		}
+	}
+
+	lambda := float64(reps) * m * m * m / (4 * year)
+	return Result{Name: "birthday-spacings", Statistic: float64(dups), P: poissonP(dups, lambda)}
+}
+
+// Gap is Knuth's gap test for the interval [0, 1/2) over about n floats.
+func Gap(g isaac.Generator, n int) Result {
+	const (
+		t = 8
+		p = 0.5
+	)
+
+	gaps := n / 2
+	observed := make([]int, t+1)
+	for i := 0; i < gaps; i++ {
+		r := 0
+		for float64s(g) >= p {
+			r++
+		}
+		if r > t {
+			r = t
+		}
+		observed[r]++
+	}
+
+	probs := make([]float64, t+1)
+	for r := 0; r < t; r++ {
+		probs[r] = p * math.Pow(1-p, float64(r))
+	}
+	probs[t] = math.Pow(1-p, t)
+
+	stat := chiSquare(observed, probs, gaps)
+	return Result{Name: "gap", Statistic: stat, P: chiSquareP(stat, t)}
+}
+
+// Poker is Knuth's simplified poker test, counting distinct values among
+// hands of five 3-bit digits. Each word yields four hands.
+func Poker(g isaac.Generator, n int) Result {
+	const (
+		k = 5
+		d = 8
+	)
+
+	// Hands with one or two distinct values are merged since the former is
+	// too rare for the chi-square approximation.
+	observed := make([]int, k-1)
+	for i := 0; i < n; i++ {
+		x := g.Uint64()
+		for h := 0; h < 4; h++ {
+			var seen uint8
+			for j := 0; j < k; j++ {
+				seen |= 1 << (x & (d - 1))
+				x >>= 3
+			}
+
+			r := bits.OnesCount8(seen)
+			if r < 2 {
+				r = 2
+			}
+			observed[r-2]++
+		}
+	}
+
+	probs := make([]float64, k-1)
+	for r := 1; r <= k; r++ {
+		prob := stirling2(k, r) / math.Pow(d, k)
+		for i := 0; i < r; i++ {
+			prob *= float64(d - i)
+		}
+		if r < 2 {
+			probs[0] += prob
+		} else {
+			probs[r-2] += prob
+		}
+	}
+
+	stat := chiSquare(observed, probs, 4*n)
+	return Result{Name: "poker", Statistic: stat, P: chiSquareP(stat, len(probs)-1)}
+}
+
+// stirling2 returns the Stirling number of the second kind S(n, k).
+func stirling2(n, k int) float64 {
+	if n == k {
+		return 1
+	}
+	if k == 0 || k > n {
+		return 0
+	}
+
+	return float64(k)*stirling2(n-1, k) + stirling2(n-1, k-1)
+}
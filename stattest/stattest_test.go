@@ -0,0 +1,135 @@
+package stattest
+
+import (
+	"flag"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/skdltmxn/go-isaac"
+)
+
+var long = flag.Bool("long", false, "run the statistical tests with large samples")
+
+// alpha is the significance level of a single test. The battery runs with
+// fixed seeds, so a failure is reproducible and not a statistical fluke.
+const alpha = 1e-4
+
+func sampleSize() int {
+	switch {
+	case *long, os.Getenv("STATTEST_LONG") != "":
+		return 1 << 22
+	case testing.Short():
+		return 1 << 14
+	}
+
+	return 1 << 16
+}
+
+func checkBattery(t *testing.T, g isaac.Generator) {
+	t.Helper()
+
+	for _, r := range Run(g, sampleSize()) {
+		t.Logf("%-20s stat=%-14.6g p=%.6f", r.Name, r.Statistic, r.P)
+		if !r.Passed(alpha) {
+			t.Errorf("%s failed: stat=%v p=%v", r.Name, r.Statistic, r.P)
+		}
+	}
+}
+
+func TestIsaac(t *testing.T) {
+	for _, seed := range []int64{0, 1, 0x5eed} {
+		isa := isaac.NewIsaac()
+		isa.Seed(seed)
+		checkBattery(t, isa)
+	}
+
+	isa := isaac.NewIsaac()
+	isa.SeedFromKey([]byte("password"), []byte("salt"), nil)
+	checkBattery(t, isa)
+}
+
+func TestIsaac64(t *testing.T) {
+	for _, seed := range []int64{0, 1, 0x5eed} {
+		isa := isaac.NewIsaac64()
+		isa.Seed(seed)
+		checkBattery(t, isa)
+	}
+
+	isa := isaac.NewIsaac64()
+	isa.SeedFromKey([]byte("password"), []byte("salt"), nil)
+	checkBattery(t, isa)
+}
+
+// TestUint32 runs the battery over the 32-bit output of both generators, in
+// every mode that changes how it is produced, and over the compat mode of
+// Isaac.Uint64.
+func TestUint32(t *testing.T) {
+	isa := isaac.NewIsaac()
+	isa.Seed(1)
+	checkBattery(t, Uint32Words(isa))
+
+	isa = isaac.NewIsaac()
+	isa.Seed(1)
+	isa.SetUint64Compat(true)
+	checkBattery(t, isa)
+
+	isa64 := isaac.NewIsaac64()
+	isa64.Seed(1)
+	checkBattery(t, Uint32Words(isa64))
+
+	isa64 = isaac.NewIsaac64()
+	isa64.Seed(1)
+	isa64.SetHalfWords(true)
+	checkBattery(t, Uint32Words(isa64))
+}
+
+// counter is a deliberately poor generator.
+type counter struct {
+	isaac.Generator
+	n uint64
+}
+
+func (c *counter) Uint64() uint64 {
+	c.n += 0x9e3779b97f4a7c15
+	return c.n
+}
+
+func (c *counter) Uint32() uint32 {
+	return uint32(c.Uint64() >> 32)
+}
+
+func TestBatteryRejects(t *testing.T) {
+	for _, g := range []isaac.Generator{&counter{}, Uint32Words(&counter{})} {
+		failed := 0
+		for _, r := range Run(g, sampleSize()) {
+			if !r.Passed(alpha) {
+				failed++
+			}
+		}
+
+		if failed == 0 {
+			t.Fatal("battery accepted a Weyl sequence")
+		}
+	}
+}
+
+func TestSpecial(t *testing.T) {
+	cases := []struct {
+		got, want float64
+	}{
+		// chi-square with 2 degrees of freedom has survival exp(-x/2)
+		{chiSquareP(3, 2), math.Exp(-1.5)},
+		{chiSquareP(18.307038, 10), 0.05},
+		{normalP(1.959963984540054), 0.05},
+		// Poisson(2): P(X <= 0) = e^-2, so the two-sided value doubles it
+		{poissonP(0, 2), 2 * math.Exp(-2)},
+		{stirling2(5, 2), 15},
+	}
+
+	for i, c := range cases {
+		if math.Abs(c.got-c.want) > 1e-4 {
+			t.Errorf("[%v] %v expected but found %v", i, c.want, c.got)
+		}
+	}
+}
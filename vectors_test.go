@@ -0,0 +1,54 @@
+package isaac
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skdltmxn/go-isaac/internal/vectorfile"
+)
+
+// vectorFile holds reference vectors read from a file in testdata.
+type vectorFile struct {
+	name string
+	*vectorfile.File
+}
+
+func readVectors(t *testing.T, path string) vectorFile {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	vf, err := vectorfile.Parse(f, "")
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+
+	return vectorFile{name: filepath.Base(path), File: vf}
+}
+
+// loadVectors returns every vector file in testdata generated for alg.
+func loadVectors(t *testing.T, alg string) []vectorFile {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var files []vectorFile
+	for _, path := range paths {
+		if vf := readVectors(t, path); vf.Alg == alg {
+			files = append(files, vf)
+		}
+	}
+	if len(files) == 0 {
+		t.Fatalf("no %s vectors found in testdata", alg)
+	}
+
+	return files
+}
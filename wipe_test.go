@@ -0,0 +1,114 @@
+package isaac
+
+import (
+	"reflect"
+	"testing"
+)
+
+func expectWiped(t *testing.T, name string, f func()) {
+	t.Helper()
+
+	defer func() {
+		if r := recover(); r != errWiped {
+			t.Fatalf("%s: expected panic %v, got %v", name, errWiped, r)
+		}
+	}()
+
+	f()
+}
+
+func TestWipe(t *testing.T) {
+	isa := NewIsaac()
+	isa.SeedString("secret key")
+	isa.Uint32()
+	isa.Wipe()
+
+	if *isa != (Isaac{wiped: true}) {
+		t.Fatal("state is not cleared after wipe")
+	}
+
+	expectWiped(t, "Uint32", func() { isa.Uint32() })
+	expectWiped(t, "Uint64", func() { isa.Uint64() })
+	expectWiped(t, "Seed", func() { isa.Seed(1) })
+	expectWiped(t, "SeedBytes", func() { isa.SeedBytes([]byte("secret")) })
+
+	if *isa != (Isaac{wiped: true}) {
+		t.Fatal("state is modified after wipe")
+	}
+}
+
+func TestIsaac64Wipe(t *testing.T) {
+	isa := NewIsaac64()
+	isa.SeedString("secret key")
+	isa.Uint64()
+	isa.Wipe()
+
+	if *isa != (Isaac64{wiped: true}) {
+		t.Fatal("state is not cleared after wipe")
+	}
+
+	expectWiped(t, "Uint32", func() { isa.Uint32() })
+	expectWiped(t, "Uint64", func() { isa.Uint64() })
+	expectWiped(t, "Seed", func() { isa.Seed(1) })
+	expectWiped(t, "SeedBytes", func() { isa.SeedBytes([]byte("secret")) })
+
+	if *isa != (Isaac64{wiped: true}) {
+		t.Fatal("state is modified after wipe")
+	}
+}
+
+// TestWipeScrubs checks every word of a used state against zero, so that it
+// fails if Wipe leaves any part of the state behind.
+func TestWipeScrubs(t *testing.T) {
+	isa := NewIsaac()
+	isa.EnableHealthTests(HealthConfig{})
+	isa.SeedString("secret key")
+	isa.Write([]byte("more secret"))
+	isa.Bool()
+	h := isa.health
+	isa.Wipe()
+
+	for i := range isa.randrsl {
+		if isa.randrsl[i] != 0 || isa.randmem[i] != 0 {
+			t.Fatalf("[%v] word not cleared", i)
+		}
+	}
+	if isa.aa != 0 || isa.bb != 0 || isa.cc != 0 || isa.absorbed != 0 || isa.resv != (bitReservoir{}) {
+		t.Fatal("registers not cleared")
+	}
+	if isa.health != nil || !reflect.DeepEqual(*h, healthMonitor{}) {
+		t.Fatal("health test samples not cleared")
+	}
+	if _, err := isa.MarshalBinary(); err != errWiped {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if _, err := isa.Write([]byte("x")); err != errWiped {
+		t.Fatalf("Write: %v", err)
+	}
+
+	isa64 := NewIsaac64()
+	isa64.EnableHealthTests(HealthConfig{})
+	isa64.SeedString("secret key")
+	isa64.Write([]byte("more secret"))
+	isa64.Bool()
+	h64 := isa64.health
+	isa64.Wipe()
+
+	for i := range isa64.randrsl {
+		if isa64.randrsl[i] != 0 || isa64.randmem[i] != 0 {
+			t.Fatalf("[%v] word not cleared", i)
+		}
+	}
+	if isa64.aa != 0 || isa64.bb != 0 || isa64.cc != 0 || isa64.absorbed != 0 || isa64.resv != (bitReservoir{}) {
+		t.Fatal("registers not cleared")
+	}
+	if isa64.health != nil || !reflect.DeepEqual(*h64, healthMonitor{}) {
+		t.Fatal("health test samples not cleared")
+	}
+	if _, err := isa64.MarshalBinary(); err != errWiped {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if _, err := isa64.Write([]byte("x")); err != errWiped {
+		t.Fatalf("Write: %v", err)
+	}
+}